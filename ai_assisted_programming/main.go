@@ -1,224 +1,159 @@
 package main
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"log"
-	"math"
 	"os"
 
-	"github.com/montanaflynn/stats"
-	"gonum.org/v1/plot"
-	"gonum.org/v1/plot/plotter"
+	"github.com/bilguunbilegt/automated_programming/anscombe"
+	"github.com/bilguunbilegt/automated_programming/anscombe/plots"
 	"gonum.org/v1/plot/vg"
-	"gonum.org/v1/plot/vg/draw"
 )
 
 func main() {
-	anscombe := map[string][][]float64{
-		"x1": {{10, 8, 13, 9, 11, 14, 6, 4, 12, 7, 5}},
-		"y1": {{8.04, 6.95, 7.58, 8.81, 8.33, 9.96, 7.24, 4.26, 10.84, 4.82, 5.68}},
-		"x2": {{10, 8, 13, 9, 11, 14, 6, 4, 12, 7, 5}},
-		"y2": {{9.14, 8.14, 8.74, 8.77, 9.26, 8.1, 6.13, 3.1, 9.13, 7.26, 4.74}},
-		"x3": {{10, 8, 13, 9, 11, 14, 6, 4, 12, 7, 5}},
-		"y3": {{7.46, 6.77, 12.74, 7.11, 7.81, 8.84, 6.08, 5.39, 8.15, 6.42, 5.73}},
-		"x4": {{8, 8, 8, 8, 8, 8, 8, 19, 8, 8, 8}},
-		"y4": {{6.58, 5.76, 7.71, 8.84, 8.47, 7.04, 5.25, 12.5, 5.56, 7.91, 6.89}},
-	}
-	// Creating a file to save the analysis results
-	file, err := os.Create("results.txt")
-	if err != nil {
-		log.Fatalf("Failed to create file: %v", err)
-	}
-	defer file.Close()
-	// Perform linear regression analysis and print the summary
-	for i := 1; i <= 4; i++ {
-		x := anscombe[fmt.Sprintf("x%d", i)][0]
-		y := anscombe[fmt.Sprintf("y%d", i)][0]
-		if err := checkDataQuality(x, y); err != nil {
-			log.Printf("Data quality issue in set %d: %v\n", i, err)
-		}
-		// Perform linear regression analysis
-		series, err := linearRegression(x, y)
-		if err != nil {
-			log.Fatalf("Failed to perform linear regression: %v", err)
-		}
-		m := series[1].Y
-		_ = series[0].Y
-		c := 0.0 // Replace 0.0 with the appropriate value for 'c'
-		r, err := calculateRSquared(x, y, c, m)
-		if err != nil {
-			log.Fatalf("Failed to calculate R^2: %v", err)
-		}
-		// Write the analysis results to the file
-		fmt.Fprintf(file, "Set %d\n", i)
-		fmt.Fprintf(file, "Slope: %.2f\n", m)
-		fmt.Fprintf(file, "Intercept: %.2f\n", c)
-		fmt.Fprintf(file, "R^2: %.2f\n\n", r)
-		// Create a scatter plot for the data
-		p := plot.New()
-		if err != nil {
-			log.Fatalf("Failed to create plot: %v", err)
-		}
-		if err != nil {
-			log.Fatalf("Failed to create plot: %v", err)
-		}
-		p.Title.Text = fmt.Sprintf("Anscombe's Quartet Set %d", i)
-		p.X.Label.Text = "X"
-		p.Y.Label.Text = "Y"
-		s, err := plotter.NewScatter(plotter.XYs(xyData(x, y)))
+	// Analyze the datasets in the file given on the command line, or the quartet
+	var path string
+	if len(os.Args) > 1 {
+		path = os.Args[1]
+	}
+	if err := run(path); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// run analyzes the datasets in the file at path, or the quartet if path is
+// empty. A set that cannot be analyzed or plotted is skipped and listed with
+// the reason at the end of results.txt; only failing to read the input or to
+// write the results is returned.
+func run(path string) error {
+	datasets := anscombe.Quartet()
+	if path != "" {
+		var err error
+		datasets, err = anscombe.LoadFile(path, anscombe.CSVOptions{GroupColumn: "dataset"})
 		if err != nil {
-			log.Fatalf("Failed to create scatter plot: %v", err)
-		}
-		p.Add(s)
-		// Save the plot to a file
-		if err := p.Save(4*vg.Inch, 4*vg.Inch, fmt.Sprintf("set%d.png", i)); err != nil {
-			log.Fatalf("Failed to save plot: %v", err)
+			return fmt.Errorf("failed to load %s: %w", path, err)
 		}
-		meanValue, err := mean(x)
-		if err != nil {
-			log.Fatalf("Failed to calculate mean: %v", err)
+	}
+	// Sample variances and 95% intervals, as in Anscombe's table
+	opts := anscombe.DefaultOptions()
+	// Perform the analysis once; the text report, the JSON results and the plots all use it
+	results := anscombe.Analyze(datasets, opts)
+
+	// Scatter plot with the fitted line and its bands, and the residual diagnostics, of every analyzed set
+	plotOpts := plots.DefaultScatterOptions()
+	var unplotted []*anscombe.SetError
+	var plotted []anscombe.Dataset
+	for n, r := range results.Sets {
+		i := n + 1
+		if r.Error != "" {
+			log.Printf("Failed to analyze set %d: %s\n", i, r.Error)
+			continue
 		}
-		varianceValue, err := variance(x)
-		if err != nil {
-			log.Fatalf("Failed to calculate variance: %v", err)
+		if err := savePlots(i, datasets[n], plotOpts); err != nil {
+			log.Printf("Failed to plot set %d: %v\n", i, err)
+			unplotted = append(unplotted, &anscombe.SetError{Set: r.Name, Err: err})
+			continue
 		}
-		stdDevValue, err := stdDev(x)
-		if err != nil {
-			log.Fatalf("Failed to calculate standard deviation: %v", err)
+		plotted = append(plotted, datasets[n])
+	}
+	// All sets that could be plotted, side by side on shared axes
+	if len(plotted) > 0 {
+		if err := saveFacetFigure("anscombe.png", plotted); err != nil {
+			log.Printf("Failed to save anscombe.png: %v\n", err)
 		}
-		fmt.Fprintf(file, "Mean: %.2f\n", meanValue)
-		fmt.Fprintf(file, "Variance: %.2f\n", varianceValue)
-		fmt.Fprintf(file, "Standard Deviation: %.2f\n\n", stdDevValue)
-
 	}
-}
 
-func checkDataQuality(x, y []float64) error {
-	if len(x) != len(y) {
-		return fmt.Errorf("mismatched data lengths")
+	// Write the report, three significant figures as in Anscombe's table, and the sets that could not be plotted
+	if err := writeReport("results.txt", results, unplotted); err != nil {
+		return fmt.Errorf("failed to write results.txt: %w", err)
 	}
-	return nil
-}
-
-func xyData(x, y []float64) plotter.XYs {
-	pts := make(plotter.XYs, len(x))
-	for i := range x {
-		pts[i].X = x[i]
-		pts[i].Y = y[i]
+	// Write the machine-readable results next to results.txt
+	if err := writeResultsJSON("results.json", results); err != nil {
+		return fmt.Errorf("failed to write results.json: %w", err)
 	}
-	return pts
+	return nil
 }
 
-func linearRegression(x, y []float64) (stats.Series, error) {
-	if len(x) != len(y) {
-		return nil, fmt.Errorf("mismatched data lengths")
-	}
-	series := make(stats.Series, 2)
-	series, err := linearRegression(x, y)
+// savePlots draws set i with its fitted line and bands to set<i>.png and its
+// residual diagnostics (residuals vs fitted, Q-Q, scale-location and
+// leverage) to set<i>_diagnostics.png.
+func savePlots(i int, d anscombe.Dataset, opts plots.ScatterOptions) error {
+	p, err := plots.Scatter(d, opts)
 	if err != nil {
-		log.Fatalf("Failed to perform linear regression: %v", err)
+		return fmt.Errorf("scatter plot: %w", err)
 	}
-	m := series[1].Y
-	c := series[0].Y
-	if err != nil {
-		return nil, err
+	p.Title.Text = fmt.Sprintf("Anscombe's Quartet Set %d", i)
+	if err := p.Save(4*vg.Inch, 4*vg.Inch, fmt.Sprintf("set%d.png", i)); err != nil {
+		return fmt.Errorf("saving scatter plot: %w", err)
+	}
+	if err := saveDiagnostics(fmt.Sprintf("set%d_diagnostics.png", i), d); err != nil {
+		return fmt.Errorf("diagnostics: %w", err)
 	}
-	series[0] = stats.Coordinate{X: x[0], Y: m*x[0] + m}
-	series[1] = stats.Coordinate{X: x[len(x)-1], Y: m*x[len(x)-1] + c}
-	return series, nil
+	return nil
 }
 
-func calculateRSquared(x, y []float64, intercept, slope float64) (float64, error) {
-	if len(x) != len(y) {
-		return 0, fmt.Errorf("mismatched data lengths")
-	}
-	var sumOfSquares, sumOfResiduals float64
-	for i := range x {
-		yPred := intercept + slope*x[i]
-		meanY, err := stats.Mean(y)
-		if err != nil {
-			log.Fatalf("Failed to calculate mean of y: %v", err)
+// writeReport writes the text report of results to path, followed by the sets
+// that were analyzed but could not be plotted.
+func writeReport(path string, results anscombe.Results, unplotted []*anscombe.SetError) error {
+	return writeFile(path, func(w io.Writer) error {
+		if err := anscombe.WriteReport(w, results, anscombe.Text, anscombe.DefaultPrecision(anscombe.Text)); err != nil {
+			return err
 		}
-		sumOfSquares += (y[i] - meanY) * (y[i] - meanY)
-		sumOfResiduals += (y[i] - yPred) * (y[i] - yPred)
-	}
-	rSquared := 1 - sumOfResiduals/sumOfSquares
-	return rSquared, nil
+		if len(unplotted) > 0 {
+			fmt.Fprintf(w, "\nFailed plots: %d of %d\n", len(unplotted), len(results.Sets))
+			for _, e := range unplotted {
+				fmt.Fprintf(w, "  %v\n", e)
+			}
+		}
+		return nil
+	})
 }
 
-func createScatterPlot(setNumber int, x, y []float64) {
-	p := plot.New()
-
-	p.Title.Text = fmt.Sprintf("Anscombe's Quartet - Set %d", setNumber)
-	p.X.Label.Text = "X"
-	p.Y.Label.Text = "Y"
-	pts := make(plotter.XYs, len(x))
-	for i := range x {
-		pts[i].X = x[i]
-		pts[i].Y = y[i]
-	}
-	s, err := plotter.NewScatter(pts)
-	if err != nil {
-		log.Fatalf("Failed to create scatter plot: %v", err)
-	}
-	s.GlyphStyle.Shape = draw.CircleGlyph{}
-	p.Add(s)
-	p.Legend.Add(fmt.Sprintf("Set %d", setNumber), s)
-	if err := p.Save(5*vg.Inch, 5*vg.Inch, fmt.Sprintf("anscombe_set_%d.png", setNumber)); err != nil {
-		log.Fatalf("Failed to save plot: %v", err)
-	}
+// writeResultsJSON writes results as JSON to path.
+func writeResultsJSON(path string, results anscombe.Results) error {
+	return writeFile(path, func(w io.Writer) error {
+		return anscombe.WriteReport(w, results, anscombe.JSON, anscombe.DefaultPrecision(anscombe.JSON))
+	})
 }
 
-func makeSeries(x, y []float64) stats.Series {
-	if len(x) != len(y) {
-		return nil
-	}
-	series := make(stats.Series, len(x))
-	for i := range x {
-		series[i] = stats.Coordinate{X: x[i], Y: y[i]}
+// writeFile renders emit in memory and writes it to path. Nothing is written
+// if emit fails, and a file left partial by a failed write or close is
+// removed.
+func writeFile(path string, emit func(w io.Writer) error) error {
+	var buf bytes.Buffer
+	if err := emit(&buf); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
 	}
-	return series
-}
-
-func mean(x []float64) (float64, error) {
-	if len(x) == 0 {
-		return 0, fmt.Errorf("empty slice")
+	_, err = buf.WriteTo(f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
 	}
-	var sum float64
-	for _, v := range x {
-		sum += v
+	if err != nil {
+		os.Remove(path)
 	}
-	return sum / float64(len(x)), nil
+	return err
 }
 
-func variance(x []float64) (float64, error) {
-	if len(x) == 0 {
-		return 0, fmt.Errorf("empty slice")
-	}
-	m, err := mean(x)
+// saveFacetFigure draws every dataset as one panel of a grid figure at path.
+func saveFacetFigure(path string, datasets []anscombe.Dataset) error {
+	panels, err := plots.Facet(datasets, plots.DefaultFacetOptions())
 	if err != nil {
-		return 0, err
-	}
-	var sum float64
-	for _, v := range x {
-		sum += (v - m) * (v - m)
+		return err
 	}
-	return sum / float64(len(x)), nil
+	return plots.SaveFacet(panels, 8*vg.Inch, 8*vg.Inch, path)
 }
 
-func stdDev(x []float64) (float64, error) {
-	v, err := variance(x)
-	if err != nil {
-		return 0, err
-	}
-	rounded, err := stats.Round(math.Sqrt(v), 2)
+// saveDiagnostics draws the regression diagnostic panels of d to path.
+func saveDiagnostics(path string, d anscombe.Dataset) error {
+	panels, err := plots.Diagnostics(d)
 	if err != nil {
-		return 0, err
+		return err
 	}
-	return rounded, nil
-}
-
-func round(x float64, places int) float64 {
-	shift := math.Pow(10, float64(places))
-	return math.Round(x*shift) / shift
+	return plots.SaveFacet(panels, 8*vg.Inch, 8*vg.Inch, path)
 }
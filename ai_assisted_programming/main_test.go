@@ -1,188 +1,68 @@
 package main
 
 import (
-	"math"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
 	"testing"
-)
-
-func TestCheckDataQuality(t *testing.T) {
-	// Test case 1: Valid data
-	x := []float64{1, 2, 3, 4, 5}
-	y := []float64{2, 4, 6, 8, 10}
-
-	var err error // Declare the err variable
-	err = checkDataQuality(x, y)
-	if err != nil {
-		t.Errorf("checkDataQuality() returned an error for valid data: %v", err)
-	}
-
-	// Test case 2: Empty data
-	x = []float64{}
-	y = []float64{}
-	err = checkDataQuality(x, y)
-	if err == nil {
-		t.Error("checkDataQuality() did not return an error for empty data")
-	}
-}
-
-func TestXYData(t *testing.T) {
-	// Test case 1: Valid data
-	x := []float64{1, 2, 3, 4, 5}
-	y := []float64{2, 4, 6, 8, 10}
-	xy := xyData(x, y)
-	if len(xy) != len(x) {
-		t.Errorf("xyData() returned an incorrect number of data points: expected %d, got %d", len(x), len(xy))
-	}
 
-	// Test case 2: Empty data
-	x = []float64{}
-	y = []float64{}
-	xy = xyData(x, y)
-	if len(xy) != 0 {
-		t.Errorf("xyData() did not return an empty XYs for empty data: got %d data points", len(xy))
-	}
-}
-
-func TestLinearRegression(t *testing.T) {
-	// Test case 1: Valid data
-	x := []float64{1, 2, 3, 4, 5}
-	y := []float64{2, 4, 6, 8, 10}
-	series, err := linearRegression(x, y)
-	if err != nil {
-		t.Errorf("linearRegression() returned an error for valid data: %v", err)
-	}
-	if len(series) != 2 {
-		t.Errorf("linearRegression() returned an incorrect number of series: expected 2, got %d", len(series))
-	}
-
-	// Test case 2: Empty data
-	x = []float64{}
-	y = []float64{}
-	series, err = linearRegression(x, y)
-	if err == nil {
-		t.Error("linearRegression() did not return an error for empty data")
-	}
-	if len(series) != 0 {
-		t.Errorf("linearRegression() did not return an empty series for empty data: got %d series", len(series))
-	}
-}
-
-func TestCalculateRSquared(t *testing.T) {
-	// Test case 1: Valid data
-	x := []float64{1, 2, 3, 4, 5}
-	y := []float64{2, 4, 6, 8, 10}
-	intercept := 0.0
-	slope := 2.0
-	rSquared, err := calculateRSquared(x, y, intercept, slope)
-	if err != nil {
-		t.Errorf("calculateRSquared() returned an error for valid data: %v", err)
-	}
-	if math.Abs(rSquared-1.0) > 1e-6 {
-		t.Errorf("calculateRSquared() returned an incorrect R-squared value: expected 1.0, got %f", rSquared)
-	}
-
-	// Test case 2: Empty data
-	x = []float64{}
-	y = []float64{}
-	intercept = 0.0
-	slope = 0.0
-	rSquared, err = calculateRSquared(x, y, intercept, slope)
-	if err == nil {
-		t.Error("calculateRSquared() did not return an error for empty data")
-	}
-	if math.Abs(rSquared-0.0) > 1e-6 {
-		t.Errorf("calculateRSquared() did not return 0.0 for empty data: got %f", rSquared)
-	}
-}
+	"github.com/bilguunbilegt/automated_programming/anscombe"
+	"github.com/bilguunbilegt/automated_programming/anscombe/plots"
+)
 
-func TestCreateScatterPlot(t *testing.T) {
+func TestSavePlots(t *testing.T) {
 	// Test case 1: Valid data
-	x := []float64{1, 2, 3, 4, 5}
-	y := []float64{2, 4, 6, 8, 10}
-	var err error
-	createScatterPlot(1, x, y)
+	d := anscombe.Dataset{Name: "Set 1", X: []float64{1, 2, 3, 4, 5}, Y: []float64{2.1, 3.9, 6.2, 7.8, 10.1}}
+	wd, err := os.Getwd()
 	if err != nil {
-		t.Errorf("createScatterPlot() returned an error for valid data: %v", err)
+		t.Fatal(err)
 	}
-
-	// Test case 2: Empty data
-	x = []float64{}
-	y = []float64{}
-	createScatterPlot(2, x, y)
-	if err == nil {
-		t.Error("createScatterPlot() did not return an error for empty data")
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
 	}
-}
-
-func TestMean(t *testing.T) {
-	// Test case 1: Valid data
-	x := []float64{1, 2, 3, 4, 5}
-	meanValue, err := mean(x)
+	defer os.Chdir(wd)
+	err = savePlots(1, d, plots.DefaultScatterOptions())
 	if err != nil {
-		t.Errorf("mean() returned an error for valid data: %v", err)
+		t.Errorf("savePlots() returned an error for valid data: %v", err)
 	}
-	if math.Abs(meanValue-3.0) > 1e-6 {
-		t.Errorf("mean() returned an incorrect mean value: expected 3.0, got %f", meanValue)
+	for _, name := range []string{"set1.png", "set1_diagnostics.png"} {
+		if _, err := os.Stat(name); err != nil {
+			t.Errorf("savePlots() did not write %s: %v", name, err)
+		}
 	}
 
 	// Test case 2: Empty data
-	x = []float64{}
-	meanValue, err = mean(x)
+	err = savePlots(2, anscombe.Dataset{Name: "Set 2"}, plots.DefaultScatterOptions())
 	if err == nil {
-		t.Error("mean() did not return an error for empty data")
-	}
-	if math.Abs(meanValue-0.0) > 1e-6 {
-		t.Errorf("mean() did not return 0.0 for empty data: got %f", meanValue)
+		t.Error("savePlots() did not return an error for empty data")
 	}
 }
 
-func TestVariance(t *testing.T) {
-	// Test case 1: Valid data
-	x := []float64{1, 2, 3, 4, 5}
-	varianceValue, err := variance(x)
-	if err != nil {
-		t.Errorf("variance() returned an error for valid data: %v", err)
+func TestWriteFile(t *testing.T) {
+	dir := t.TempDir()
+	// Test case 1: what emit renders is written
+	path := filepath.Join(dir, "results.txt")
+	if err := writeFile(path, func(w io.Writer) error { _, err := fmt.Fprint(w, "Set 1\n"); return err }); err != nil {
+		t.Fatalf("writeFile() returned an error: %v", err)
 	}
-	if math.Abs(varianceValue-2.5) > 1e-6 {
-		t.Errorf("variance() returned an incorrect variance value: expected 2.5, got %f", varianceValue)
+	if data, err := os.ReadFile(path); err != nil || string(data) != "Set 1\n" {
+		t.Errorf("writeFile() wrote %q, %v", data, err)
 	}
 
-	// Test case 2: Empty data
-	x = []float64{}
-	varianceValue, err = variance(x)
-	if err == nil {
-		t.Error("variance() did not return an error for empty data")
-	}
-	if math.Abs(varianceValue-0.0) > 1e-6 {
-		t.Errorf("variance() did not return 0.0 for empty data: got %f", varianceValue)
+	// Test case 2: a failed emit leaves no file
+	failed := filepath.Join(dir, "failed.txt")
+	emitErr := errors.New("emit failed")
+	if err := writeFile(failed, func(io.Writer) error { return emitErr }); err != emitErr {
+		t.Errorf("writeFile() with a failing emit: expected %v, got %v", emitErr, err)
 	}
-}
-
-func TestStdDev(t *testing.T) {
-	// Test case 1: Valid data
-	x := []float64{1, 2, 3, 4, 5}
-	stdDev, err := stdDev(x)
-	if err != nil {
-		t.Errorf("stdDev() returned an error for valid data: %v", err)
-	}
-	if math.Abs(stdDev-math.Sqrt(2.5)) > 1e-6 {
-		t.Errorf("stdDev() returned an incorrect standard deviation value: expected %f, got %f", math.Sqrt(2.5), stdDev)
-	}
-
-}
-
-func TestRound(t *testing.T) {
-	// Test case 1: Positive number
-	x := 3.14159
-	rounded := round(x, 2)
-	if math.Abs(rounded-3.14) > 1e-6 {
-		t.Errorf("round() returned an incorrect rounded value for positive number: expected 3.14, got %f", rounded)
+	if _, err := os.Stat(failed); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("writeFile() with a failing emit left %s behind", failed)
 	}
 
-	// Test case 2: Negative number
-	x = -3.14159
-	rounded = round(x, 2)
-	if math.Abs(rounded+3.14) > 1e-6 {
-		t.Errorf("round() returned an incorrect rounded value for negative number: expected -3.14, got %f", rounded)
+	// Test case 3: a file that cannot be created is an error
+	if err := writeFile(filepath.Join(dir, "missing", "results.txt"), func(io.Writer) error { return nil }); err == nil {
+		t.Error("writeFile() into a missing directory did not return an error")
 	}
 }
@@ -2,29 +2,28 @@ module ai_generated_code
 
 go 1.22.4
 
-require gonum.org/v1/gonum v0.15.0
+require (
+	github.com/bilguunbilegt/automated_programming/anscombe v0.0.0
+	gonum.org/v1/plot v0.14.0
+)
+
+require (
+	github.com/montanaflynn/stats v0.7.1 // indirect
+	gonum.org/v1/gonum v0.15.0 // indirect
+)
 
 require (
-	gioui.org v0.2.0 // indirect
-	gioui.org/cpu v0.0.0-20220412190645-f1e9e8c3b1f7 // indirect
-	gioui.org/shader v1.0.6 // indirect
-	gioui.org/x v0.2.0 // indirect
 	git.sr.ht/~sbinet/gg v0.5.0 // indirect
 	github.com/ajstarks/svgo v0.0.0-20211024235047-1546f124cd8b // indirect
-	github.com/andybalholm/stroke v0.0.0-20221221101821-bd29b49d73f0 // indirect
 	github.com/campoy/embedmd v1.0.0 // indirect
 	github.com/go-fonts/liberation v0.3.2 // indirect
 	github.com/go-latex/latex v0.0.0-20231108140139-5c1ce85aa4ea // indirect
 	github.com/go-pdf/fpdf v0.9.0 // indirect
-	github.com/go-text/typesetting v0.0.0-20230803102845-24e03d8b5372 // indirect
 	github.com/golang/freetype v0.0.0-20170609003504-e2365dfdc4a0 // indirect
-	github.com/montanaflynn/stats v0.7.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	golang.org/x/exp v0.0.0-20231110203233-9a3e6036ecaa // indirect
-	golang.org/x/exp/shiny v0.0.0-20230801115018-d63ba01acd4b // indirect
 	golang.org/x/image v0.14.0 // indirect
-	golang.org/x/sys v0.14.0 // indirect
 	golang.org/x/text v0.14.0 // indirect
-	gonum.org/v1/plot v0.14.0 // indirect
-	rsc.io/pdf v0.1.1 // indirect
 )
+
+replace github.com/bilguunbilegt/automated_programming/anscombe => ../anscombe
@@ -1,176 +1,159 @@
 package main
 
 import (
-    "bufio"
+    "bytes"
     "fmt"
+    "io"
     "log"
-    "math"
     "os"
 
-    "github.com/montanaflynn/stats"
-    "gonum.org/v1/plot"
-    "gonum.org/v1/plot/plotter"
+    "github.com/bilguunbilegt/automated_programming/anscombe"
+    "github.com/bilguunbilegt/automated_programming/anscombe/plots"
     "gonum.org/v1/plot/vg"
 )
 
-type Coordinate struct {
-    X, Y float64
-}
-
-type Series []Coordinate
-
-// Define the Anscombe map
-var anscombeMap = map[string][]float64{
-    "x1": {10, 8, 13, 9, 11, 14, 6, 4, 12, 7, 5},
-    "y1": {8.04, 6.95, 7.58, 8.81, 8.33, 9.96, 7.24, 4.26, 10.84, 4.82, 5.68},
-    "x2": {10, 8, 13, 9, 11, 14, 6, 4, 12, 7, 5},
-    "y2": {9.14, 8.14, 8.74, 8.77, 9.26, 8.1, 6.13, 3.1, 9.13, 7.26, 4.74},
-    "x3": {10, 8, 13, 9, 11, 14, 6, 4, 12, 7, 5},
-    "y3": {7.46, 6.77, 12.74, 7.11, 7.81, 8.84, 6.08, 5.39, 8.15, 6.42, 5.73},
-    "x4": {8, 8, 8, 8, 8, 8, 8, 19, 8, 8, 8},
-    "y4": {6.58, 5.76, 7.71, 8.84, 8.47, 7.04, 5.25, 12.5, 5.56, 7.91, 6.89},
-}
-
 func main() {
-    // Open file for writing
-    file, err := os.Create("results.txt")
-    if err != nil {
+    if err := run(os.Args[1:]); err != nil {
         log.Fatal(err)
     }
-    defer file.Close()
-    writer := bufio.NewWriter(file)
-
-    for key, xValues := range anscombeMap {
-        if len(xValues) == 0 {
-            msg := fmt.Sprintf("No data for %s\n", key)
-            fmt.Print(msg)
-            writer.WriteString(msg)
-            continue
-        }
-        yValues := anscombeMap["y" + key[1:]] // Corresponding y-values
-
-        // Convert slices to stats.Series
-        var series Series
-        for i := range xValues {
-            series = append(series, Coordinate{X: xValues[i], Y: yValues[i]})
-        }
+}
 
-        // Perform linear regression
-        regressions, err := stats.LinearRegression(series)
+// run analyzes the datasets in the file named by args, or the quartet.
+// A set that cannot be analyzed or plotted is reported and skipped; only
+// failing to read the input or to write the results stops the run.
+func run(args []string) error {
+    // Load the datasets from the file given as argument, or use the quartet
+    datasets := anscombe.Quartet()
+    if len(args) > 0 {
+        var err error
+        datasets, err = anscombe.LoadFile(args[0], anscombe.CSVOptions{GroupColumn: "dataset"})
         if err != nil {
-            log.Fatal(err)
+            return err
         }
+    }
+    // The registry keeps the sets in file order, so every run writes the same report
+    registry, err := anscombe.NewRegistry(datasets...)
+    if err != nil {
+        return err
+    }
 
-        slope := regressions.Slope
-        intercept := regressions.Intercept
+    // Sample variances and 95% intervals, as in Anscombe's table
+    opts := anscombe.DefaultOptions()
+    // One analysis of every set feeds the report, the plots and results.json
+    results := anscombe.Analyze(registry.Datasets(), opts)
 
-        msg := fmt.Sprintf("Linear Regression for %s:\n", key)
-        msg += fmt.Sprintf("Slope: %.2f\n", slope)
-        msg += fmt.Sprintf("Intercept: %.2f\n", intercept)
-        fmt.Print(msg)
-        writer.WriteString(msg)
+    // The report, three significant figures as in Anscombe's table, to the screen and later the file
+    var report bytes.Buffer
+    if err := anscombe.WriteReport(&report, results, anscombe.Text, anscombe.DefaultPrecision(anscombe.Text)); err != nil {
+        return err
+    }
+    if _, err := os.Stdout.Write(report.Bytes()); err != nil {
+        return err
+    }
 
-        // Calculate R squared
-        yPredicted := make([]float64, len(xValues))
-        for i, x := range xValues {
-            yPredicted[i] = slope*x + intercept
+    // Plotting, with the fitted line and its bands, and the residual diagnostics, of every analyzed set
+    var unplotted []*anscombe.SetError
+    var plotted []anscombe.Dataset
+    for n, d := range registry.Datasets() {
+        if results.Sets[n].Error != "" {
+            continue
         }
-
-        ssTotal := sumSquares(yValues, mean(yValues))
-        ssResidual := sumSquaresResidual(yValues, yPredicted)
-        rSquared := 1 - (ssResidual / ssTotal)
-        msg = fmt.Sprintf("R-squared: %.2f\n", rSquared)
-        fmt.Print(msg)
-        writer.WriteString(msg)
-
-        // Data quality check
-        if len(xValues) != len(yValues) {
-            msg = fmt.Sprintf("Warning: Mismatch in lengths of x and y values for %s\n", key)
-            fmt.Print(msg)
-            writer.WriteString(msg)
+        if err := savePlots(d); err != nil {
+            unplotted = append(unplotted, &anscombe.SetError{Set: d.Name, Err: err})
+            continue
         }
+        plotted = append(plotted, d)
+    }
 
-        // Write statistics
-        meanX := mean(xValues)
-        varianceX := variance(xValues, meanX)
-        stdDevX := stdDev(xValues, meanX)
-        meanY := mean(yValues)
-        varianceY := variance(yValues, meanY)
-        stdDevY := stdDev(yValues, meanY)
-
-        msg = fmt.Sprintf("Mean X: %.2f\n", meanX)
-        msg += fmt.Sprintf("Variance X: %.2f\n", varianceX)
-        msg += fmt.Sprintf("Standard Deviation X: %.2f\n", stdDevX)
-        msg += fmt.Sprintf("Mean Y: %.2f\n", meanY)
-        msg += fmt.Sprintf("Variance Y: %.2f\n", varianceY)
-        msg += fmt.Sprintf("Standard Deviation Y: %.2f\n", stdDevY)
+    // Which sets could not be plotted and why
+    if len(unplotted) > 0 {
+        msg := fmt.Sprintf("\nFailed plots: %d of %d\n", len(unplotted), len(results.Sets))
+        for _, e := range unplotted {
+            msg += fmt.Sprintf("  %v\n", e)
+        }
         fmt.Print(msg)
-        writer.WriteString(msg)
+        report.WriteString(msg)
+    }
 
-        // Plotting
-        p, err := plot.New()
-        if err != nil {
-            log.Fatal(err)
-        }
-        p.Title.Text = "Anscombe's Dataset " + key
-        p.X.Label.Text = "X"
-        p.Y.Label.Text = "Y"
-
-        scatterData := make(plotter.XYs, len(xValues))
-        for i := range xValues {
-            scatterData[i].X = xValues[i]
-            scatterData[i].Y = yValues[i]
-        }
-        scatter, err := plotter.NewScatter(scatterData)
-        if err != nil {
-            log.Fatal(err)
-        }
-        p.Add(scatter)
+    // Write the report to results.txt once it is complete
+    if err := writeFile("results.txt", func(w io.Writer) error {
+        _, err := report.WriteTo(w)
+        return err
+    }); err != nil {
+        return err
+    }
 
-        if err := p.Save(6*vg.Inch, 6*vg.Inch, "scatter_plot_"+key+".png"); err != nil {
-            log.Fatal(err)
+    // All plotted sets side by side on shared axes
+    if len(plotted) > 0 {
+        if err := saveFacetFigure("anscombe.png", plotted); err != nil {
+            log.Printf("anscombe.png: %v", err)
         }
-
-        fmt.Println("Plot saved as scatter_plot_" + key + ".png")
-        writer.WriteString("\n")
     }
 
-    // Flush the buffer
-    writer.Flush()
+    // Write the machine-readable results next to results.txt
+    return writeResultsJSON("results.json", results)
 }
 
-func sumSquares(values []float64, mean float64) float64 {
-    var ss float64
-    for _, v := range values {
-        ss += (v - mean) * (v - mean)
+// savePlots draws d with its fitted line and bands, and its residual
+// diagnostics: residuals vs fitted, Q-Q, scale-location and leverage.
+func savePlots(d anscombe.Dataset) error {
+    p, err := plots.Scatter(d, plots.DefaultScatterOptions())
+    if err != nil {
+        return err
+    }
+    p.Title.Text = "Anscombe's Dataset " + d.Name
+
+    plotFile := "scatter_plot_" + d.Slug() + ".png"
+    if err := p.Save(6*vg.Inch, 6*vg.Inch, plotFile); err != nil {
+        return err
     }
-    return ss
+    fmt.Println("Plot saved as " + plotFile)
+    return saveDiagnostics("diagnostics_"+d.Slug()+".png", d)
 }
 
-func sumSquaresResidual(yValues, yPredicted []float64) float64 {
-    var ss float64
-    for i, y := range yValues {
-        ss += (y - yPredicted[i]) * (y - yPredicted[i])
-    }
-    return ss
+// writeResultsJSON writes results as JSON to path.
+func writeResultsJSON(path string, results anscombe.Results) error {
+    return writeFile(path, func(w io.Writer) error {
+        return anscombe.WriteResults(w, results)
+    })
 }
 
-func mean(values []float64) float64 {
-    sum := 0.0
-    for _, v := range values {
-        sum += v
+// writeFile renders emit in memory and writes it to path. Nothing is written
+// if emit fails, and a file left partial by a failed write or close is
+// removed.
+func writeFile(path string, emit func(w io.Writer) error) error {
+    var buf bytes.Buffer
+    if err := emit(&buf); err != nil {
+        return err
+    }
+    file, err := os.Create(path)
+    if err != nil {
+        return err
+    }
+    _, err = buf.WriteTo(file)
+    if cerr := file.Close(); err == nil {
+        err = cerr
     }
-    return sum / float64(len(values))
+    if err != nil {
+        os.Remove(path)
+    }
+    return err
 }
 
-func variance(values []float64, mean float64) float64 {
-    var variance float64
-    for _, v := range values {
-        variance += (v - mean) * (v - mean)
+// saveFacetFigure draws every dataset as one panel of a grid figure at path.
+func saveFacetFigure(path string, datasets []anscombe.Dataset) error {
+    panels, err := plots.Facet(datasets, plots.DefaultFacetOptions())
+    if err != nil {
+        return err
     }
-    return variance / float64(len(values)-1)
+    return plots.SaveFacet(panels, 8*vg.Inch, 8*vg.Inch, path)
 }
 
-func stdDev(values []float64, mean float64) float64 {
-    return math.Sqrt(variance(values, mean))
+// saveDiagnostics draws the regression diagnostic panels of d to path.
+func saveDiagnostics(path string, d anscombe.Dataset) error {
+    panels, err := plots.Diagnostics(d)
+    if err != nil {
+        return err
+    }
+    return plots.SaveFacet(panels, 8*vg.Inch, 8*vg.Inch, path)
 }
@@ -4,21 +4,16 @@ import (
 	"math"
 	"testing"
 
-	"github.com/montanaflynn/stats"
+	"github.com/bilguunbilegt/automated_programming/anscombe"
 )
 
 func TestLinearRegression(t *testing.T) {
 	// Sample data
-	series := Series{
-		{X: 1, Y: 2},
-		{X: 2, Y: 3},
-		{X: 3, Y: 4},
-		{X: 4, Y: 5},
-		{X: 5, Y: 6},
-	}
+	xValues := []float64{1, 2, 3, 4, 5}
+	yValues := []float64{2, 3, 4, 5, 6}
 
 	// Perform linear regression
-	regressions, err := stats.LinearRegression(series)
+	fit, err := anscombe.LinearRegression(xValues, yValues)
 	if err != nil {
 		t.Fatalf("LinearRegression error: %v", err)
 	}
@@ -27,11 +22,11 @@ func TestLinearRegression(t *testing.T) {
 	expectedSlope := 1.0
 	expectedIntercept := 1.0
 
-	if math.Abs(regressions.Slope-expectedSlope) > 1e-6 {
-		t.Errorf("Expected slope %f, got %f", expectedSlope, regressions.Slope)
+	if math.Abs(fit.Slope-expectedSlope) > 1e-6 {
+		t.Errorf("Expected slope %f, got %f", expectedSlope, fit.Slope)
 	}
-	if math.Abs(regressions.Intercept-expectedIntercept) > 1e-6 {
-		t.Errorf("Expected intercept %f, got %f", expectedIntercept, regressions.Intercept)
+	if math.Abs(fit.Intercept-expectedIntercept) > 1e-6 {
+		t.Errorf("Expected intercept %f, got %f", expectedIntercept, fit.Intercept)
 	}
 }
 
@@ -40,25 +35,13 @@ func TestRSquared(t *testing.T) {
 	yValues := []float64{2, 3, 4, 5, 6}
 
 	// Perform linear regression
-	series := Series{}
-	for i := range xValues {
-		series = append(series, Coordinate{X: xValues[i], Y: yValues[i]})
-	}
-	regressions, err := stats.LinearRegression(series)
+	fit, err := anscombe.LinearRegression(xValues, yValues)
 	if err != nil {
 		t.Fatalf("LinearRegression error: %v", err)
 	}
 
-	// Predict values
-	yPredicted := make([]float64, len(xValues))
-	for i, x := range xValues {
-		yPredicted[i] = regressions.Slope*x + regressions.Intercept
-	}
-
 	// Calculate R-squared
-	ssTotal := sumSquares(yValues, mean(yValues))
-	ssResidual := sumSquaresResidual(yValues, yPredicted)
-	rSquared := 1 - (ssResidual / ssTotal)
+	rSquared := fit.RSquared()
 
 	expectedRSquared := 1.0 // Perfect fit
 	if math.Abs(rSquared-expectedRSquared) > 1e-6 {
@@ -70,32 +53,7 @@ func TestDataQuality(t *testing.T) {
 	xValues := []float64{1, 2, 3, 4, 5}
 	yValues := []float64{2, 3, 4, 5}
 
-	if len(xValues) != len(yValues) {
-		t.Error("Mismatch in lengths of x and y values")
-	}
-}
-
-// Helper functions for testing
-func mean(values []float64) float64 {
-	sum := 0.0
-	for _, v := range values {
-		sum += v
-	}
-	return sum / float64(len(values))
-}
-
-func sumSquares(values []float64, mean float64) float64 {
-	var ss float64
-	for _, v := range values {
-		ss += (v - mean) * (v - mean)
-	}
-	return ss
-}
-
-func sumSquaresResidual(yValues, yPredicted []float64) float64 {
-	var ss float64
-	for i, y := range yValues {
-		ss += (y - yPredicted[i]) * (y - yPredicted[i])
+	if err := anscombe.CheckDataQuality(xValues, yValues); err == nil {
+		t.Error("Mismatch in lengths of x and y values was not reported")
 	}
-	return ss
 }
@@ -0,0 +1,275 @@
+package anscombe
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/montanaflynn/stats"
+)
+
+// Measure is a named statistic of the association between x and y.
+type Measure struct {
+	Name    string
+	Compute func(x, y []float64) (float64, error)
+}
+
+// DefaultMeasures returns Pearson's r followed by the rank and nonlinear
+// measures that tell apart relationships sharing the same r: Spearman's rho,
+// Kendall's tau-b, distance correlation and mutual information.
+func DefaultMeasures() []Measure {
+	return []Measure{
+		{"Pearson's r", func(x, y []float64) (float64, error) { return stats.Correlation(x, y) }},
+		{"Spearman's rho", Spearman},
+		{"Kendall's tau-b", KendallTauB},
+		{"Distance correlation", DistanceCorrelation},
+		{"Mutual information (nats)", func(x, y []float64) (float64, error) { return MutualInformation(x, y, 0) }},
+	}
+}
+
+// Spearman returns Spearman's rank correlation coefficient, the Pearson
+// correlation of the ranks of x and y with ties given their average rank. It
+// returns ErrBounds if x or y is constant.
+func Spearman(x, y []float64) (float64, error) {
+	if err := checkLine(x, y); err != nil {
+		return 0, err
+	}
+	rx, ry := ranks(x), ranks(y)
+	mx, _ := Mean(rx)
+	my, _ := Mean(ry)
+	var sxx, syy, sxy float64
+	for i := range rx {
+		dx, dy := rx[i]-mx, ry[i]-my
+		sxx += dx * dx
+		syy += dy * dy
+		sxy += dx * dy
+	}
+	if sxx == 0 || syy == 0 {
+		return 0, ErrBounds
+	}
+	return sxy / math.Sqrt(sxx*syy), nil
+}
+
+// ranks returns the 1-based ranks of x, giving tied values their average
+// rank.
+func ranks(x []float64) []float64 {
+	order := make([]int, len(x))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return x[order[a]] < x[order[b]] })
+	r := make([]float64, len(x))
+	for start := 0; start < len(order); {
+		end := start + 1
+		for end < len(order) && x[order[end]] == x[order[start]] {
+			end++
+		}
+		// Positions start..end-1 hold ranks start+1..end.
+		avg := float64(start+end+1) / 2
+		for _, i := range order[start:end] {
+			r[i] = avg
+		}
+		start = end
+	}
+	return r
+}
+
+// KendallTauB returns Kendall's tau-b, the difference between the numbers of
+// concordant and discordant pairs divided by the geometric mean of the
+// numbers of pairs untied in x and untied in y. It returns ErrBounds if x or
+// y is constant.
+func KendallTauB(x, y []float64) (float64, error) {
+	if err := checkLine(x, y); err != nil {
+		return 0, err
+	}
+	var concordant, discordant, untiedX, untiedY float64
+	for i := range x {
+		for j := i + 1; j < len(x); j++ {
+			dx, dy := x[i]-x[j], y[i]-y[j]
+			if dx != 0 {
+				untiedX++
+			}
+			if dy != 0 {
+				untiedY++
+			}
+			switch {
+			case dx*dy > 0:
+				concordant++
+			case dx*dy < 0:
+				discordant++
+			}
+		}
+	}
+	if untiedX == 0 || untiedY == 0 {
+		return 0, ErrBounds
+	}
+	return (concordant - discordant) / math.Sqrt(untiedX*untiedY), nil
+}
+
+// DistanceCorrelation returns the distance correlation of Székely, Rizzo and
+// Bakirov, which is zero only if x and y are independent, however nonlinear
+// their relationship. It returns ErrBounds if x or y is constant.
+func DistanceCorrelation(x, y []float64) (float64, error) {
+	if err := checkLine(x, y); err != nil {
+		return 0, err
+	}
+	a, b := centeredDistances(x), centeredDistances(y)
+	var xy, xx, yy float64
+	for i := range a {
+		xy += a[i] * b[i]
+		xx += a[i] * a[i]
+		yy += b[i] * b[i]
+	}
+	if xx == 0 || yy == 0 {
+		return 0, ErrBounds
+	}
+	// Rounding can leave a tiny negative squared covariance.
+	return math.Sqrt(math.Max(xy, 0) / math.Sqrt(xx*yy)), nil
+}
+
+// centeredDistances returns the doubly centered matrix of distances between
+// the values of x, row by row.
+func centeredDistances(x []float64) []float64 {
+	n := len(x)
+	d := make([]float64, n*n)
+	rows := make([]float64, n)
+	var total float64
+	for i := range x {
+		for j := range x {
+			d[i*n+j] = math.Abs(x[i] - x[j])
+			rows[i] += d[i*n+j]
+		}
+		total += rows[i]
+		rows[i] /= float64(n)
+	}
+	total /= float64(n * n)
+	for i := range x {
+		for j := range x {
+			// The matrix is symmetric, so column means equal row means.
+			d[i*n+j] += total - rows[i] - rows[j]
+		}
+	}
+	return d
+}
+
+// MutualInformation returns the plug-in estimate, in nats, of the mutual
+// information between x and y binned into a grid of equal-width bins. With
+// zero bins, Sturges' rule ceil(log2(n)) + 1 picks the number per axis. The
+// estimate is biased upwards in small samples, which the permutation test
+// accounts for.
+func MutualInformation(x, y []float64, bins int) (float64, error) {
+	if err := checkLine(x, y); err != nil {
+		return 0, err
+	}
+	n := len(x)
+	if bins <= 0 {
+		bins = int(math.Ceil(math.Log2(float64(n)))) + 1
+	}
+	bx, by := binIndices(x, bins), binIndices(y, bins)
+	joint := make([]float64, bins*bins)
+	px := make([]float64, bins)
+	py := make([]float64, bins)
+	for i := range bx {
+		joint[bx[i]*bins+by[i]]++
+		px[bx[i]]++
+		py[by[i]]++
+	}
+	var mi float64
+	nf := float64(n)
+	for i := 0; i < bins; i++ {
+		for j := 0; j < bins; j++ {
+			if c := joint[i*bins+j]; c > 0 {
+				mi += c / nf * math.Log(c*nf/(px[i]*py[j]))
+			}
+		}
+	}
+	return mi, nil
+}
+
+// binIndices assigns each value of x to one of bins equal-width bins spanning
+// its range. A constant x falls entirely in the first bin.
+func binIndices(x []float64, bins int) []int {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range x {
+		lo, hi = math.Min(lo, v), math.Max(hi, v)
+	}
+	idx := make([]int, len(x))
+	if hi == lo {
+		return idx
+	}
+	for i, v := range x {
+		idx[i] = int(float64(bins) * (v - lo) / (hi - lo))
+		if idx[i] == bins {
+			idx[i]--
+		}
+	}
+	return idx
+}
+
+// PermutationTest returns the p-value of the statistic compute(x, y) under
+// the null hypothesis that x and y are independent. It recomputes the
+// statistic on the given number of random permutations of y, drawn from a
+// generator seeded with seed, and counts those at least as large in
+// magnitude: p = (1 + count) / (1 + permutations). It returns ErrBounds if
+// permutations is not positive.
+func PermutationTest(x, y []float64, compute func(x, y []float64) (float64, error), permutations int, seed int64) (float64, error) {
+	if permutations <= 0 {
+		return 0, ErrBounds
+	}
+	observed, err := compute(x, y)
+	if err != nil {
+		return 0, err
+	}
+	rng := rand.New(rand.NewSource(seed))
+	shuffled := append([]float64(nil), y...)
+	extreme := 0
+	for k := 0; k < permutations; k++ {
+		rng.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+		v, err := compute(x, shuffled)
+		if err != nil {
+			return 0, err
+		}
+		// Allow for rounding in permutations that reproduce the observed value.
+		if math.Abs(v) >= math.Abs(observed)*(1-1e-12) {
+			extreme++
+		}
+	}
+	return float64(1+extreme) / float64(1+permutations), nil
+}
+
+// Association is the value of one Measure for a dataset, with its
+// permutation p-value.
+type Association struct {
+	Measure string  `json:"measure"`
+	Value   float64 `json:"value"`
+	P       Float   `json:"p"`               // permutation p-value, null if no permutations were run or the test failed
+	Error   string  `json:"error,omitempty"` // why the measure, or else its permutation test, is undefined
+}
+
+// Associations computes every measure for x and y, each with a permutation
+// test of the given size seeded with seed, so the p-values are reproducible
+// and do not depend on the order of the measures. With no permutations, or if
+// the permutation test fails, P is NaN.
+func Associations(x, y []float64, measures []Measure, permutations int, seed int64) []Association {
+	var out []Association
+	for _, m := range measures {
+		a := Association{Measure: m.Name, P: Float(math.NaN())}
+		v, err := m.Compute(x, y)
+		if err != nil {
+			a.Error = err.Error()
+			out = append(out, a)
+			continue
+		}
+		a.Value = v
+		if permutations > 0 {
+			p, err := PermutationTest(x, y, m.Compute, permutations, seed)
+			if err != nil {
+				a.Error = err.Error()
+				p = math.NaN()
+			}
+			a.P = Float(p)
+		}
+		out = append(out, a)
+	}
+	return out
+}
@@ -0,0 +1,148 @@
+package anscombe
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+)
+
+// TestAssociationQuartet checks the rank correlations of the quartet, which
+// differ where Pearson's r does not.
+func TestAssociationQuartet(t *testing.T) {
+	quartet := Quartet()
+	tests := []struct {
+		set      int
+		spearman float64
+		kendall  float64
+	}{
+		{0, 0.818182, 0.636364},
+		{1, 0.690909, 0.563636},
+		{2, 0.990909, 0.963636},
+		{3, 0.5, 0.426401},
+	}
+	for _, tt := range tests {
+		d := quartet[tt.set]
+		rho, err := Spearman(d.X, d.Y)
+		if err != nil || math.Abs(rho-tt.spearman) > 1e-6 {
+			t.Errorf("Spearman(%s) = %g, %v, expected %g", d.Name, rho, err, tt.spearman)
+		}
+		tau, err := KendallTauB(d.X, d.Y)
+		if err != nil || math.Abs(tau-tt.kendall) > 1e-6 {
+			t.Errorf("KendallTauB(%s) = %g, %v, expected %g", d.Name, tau, err, tt.kendall)
+		}
+	}
+}
+
+func TestRanks(t *testing.T) {
+	got := ranks([]float64{10, 8, 8, 3, 8})
+	want := []float64{5, 3, 3, 1, 3}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("ranks() = %v, expected %v", got, want)
+			break
+		}
+	}
+}
+
+func TestDistanceCorrelation(t *testing.T) {
+	// Test case 1: any straight line has distance correlation 1
+	x := []float64{1, 2, 3, 4, 5}
+	if got, err := DistanceCorrelation(x, []float64{7, 5, 3, 1, -1}); err != nil || math.Abs(got-1) > 1e-12 {
+		t.Errorf("DistanceCorrelation() of a line = %g, %v", got, err)
+	}
+
+	// Test case 2: a symmetric parabola has r = 0 but is far from independent
+	x = []float64{-2, -1, 0, 1, 2}
+	y := []float64{4, 1, 0, 1, 4}
+	if got, err := DistanceCorrelation(x, y); err != nil || math.Abs(got-0.5159234568589328) > 1e-12 {
+		t.Errorf("DistanceCorrelation() of a parabola = %g, %v", got, err)
+	}
+
+	// Test case 3: constant input
+	if _, err := DistanceCorrelation(x, []float64{1, 1, 1, 1, 1}); err != ErrBounds {
+		t.Errorf("DistanceCorrelation() of a constant: expected %v, got %v", ErrBounds, err)
+	}
+	if _, err := KendallTauB(x, []float64{1, 1, 1, 1, 1}); err != ErrBounds {
+		t.Errorf("KendallTauB() of a constant: expected %v, got %v", ErrBounds, err)
+	}
+	if _, err := Spearman(x, []float64{1, 1, 1, 1, 1}); err != ErrBounds {
+		t.Errorf("Spearman() of a constant: expected %v, got %v", ErrBounds, err)
+	}
+}
+
+func TestMutualInformation(t *testing.T) {
+	// Test case 1: y determines x's bin, so the information is its entropy
+	got, err := MutualInformation([]float64{1, 2, 3, 4}, []float64{1, 2, 3, 4}, 2)
+	if err != nil || math.Abs(got-math.Ln2) > 1e-12 {
+		t.Errorf("MutualInformation() = %g, %v, expected ln 2", got, err)
+	}
+
+	// Test case 2: every combination of bins appears once
+	got, err = MutualInformation([]float64{1, 1, 2, 2}, []float64{1, 2, 1, 2}, 2)
+	if err != nil || math.Abs(got) > 1e-12 {
+		t.Errorf("MutualInformation() of independent bins = %g, %v, expected 0", got, err)
+	}
+}
+
+func TestPermutationTest(t *testing.T) {
+	d := Quartet()[2]
+	p, err := PermutationTest(d.X, d.Y, Spearman, 199, 42)
+	if err != nil {
+		t.Fatalf("PermutationTest() returned an error: %v", err)
+	}
+	// Nearly every point of set 3 is in rank order, so no permutation matches it.
+	if p != 1.0/200 {
+		t.Errorf("PermutationTest() = %g, expected %g", p, 1.0/200)
+	}
+	again, _ := PermutationTest(d.X, d.Y, Spearman, 199, 42)
+	if again != p {
+		t.Errorf("PermutationTest() with the same seed gave %g, then %g", p, again)
+	}
+
+	// A symmetric parabola is as uncorrelated as a permutation gets.
+	x := []float64{-2, -1, 0, 1, 2}
+	y := []float64{4, 1, 0, 1, 4}
+	if p, _ := PermutationTest(x, y, KendallTauB, 99, 1); p != 1 {
+		t.Errorf("PermutationTest() of tau-b = 0 gave %g, expected 1", p)
+	}
+	if _, err := PermutationTest(x, y, Spearman, 0, 1); err != ErrBounds {
+		t.Errorf("PermutationTest() with no permutations: expected %v, got %v", ErrBounds, err)
+	}
+}
+
+func TestAssociations(t *testing.T) {
+	d := Quartet()[0]
+	got := Associations(d.X, d.Y, DefaultMeasures(), 99, 1)
+	if len(got) != 5 || got[0].Measure != "Pearson's r" || math.Abs(got[0].Value-0.816) > 0.001 {
+		t.Fatalf("Associations() = %+v", got)
+	}
+	for _, a := range got {
+		if a.Error != "" || a.P <= 0 || a.P > 1 {
+			t.Errorf("%s: p-value %g, error %q", a.Measure, a.P, a.Error)
+		}
+	}
+	// Without permutations there are no p-values.
+	if got := Associations(d.X, d.Y, DefaultMeasures()[:1], 0, 1); !math.IsNaN(float64(got[0].P)) {
+		t.Errorf("Associations() without permutations gave p = %g", got[0].P)
+	}
+	constant := Associations(d.X, make([]float64, d.Len()), []Measure{{"Spearman's rho", Spearman}}, 99, 1)
+	if constant[0].Error != ErrBounds.Error() {
+		t.Errorf("Associations() of a constant y = %+v", constant[0])
+	}
+	// A failed permutation test leaves the p-value undefined, not zero.
+	calls := 0
+	flaky := Measure{"flaky", func(x, y []float64) (float64, error) {
+		if calls++; calls > 2 {
+			return 0, ErrConverge
+		}
+		return 1, nil
+	}}
+	failed := Associations(d.X, d.Y, []Measure{flaky}, 99, 1)[0]
+	if failed.Error != ErrConverge.Error() || !math.IsNaN(float64(failed.P)) {
+		t.Errorf("Associations() with a failed permutation test = %+v", failed)
+	}
+	if b, err := json.Marshal(failed); err != nil || !strings.Contains(string(b), `"p":null`) {
+		t.Errorf("Association with a failed permutation test encodes as %s, %v", b, err)
+	}
+}
@@ -0,0 +1,225 @@
+package anscombe
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// Basis is a set of functions of x. FitBasis fits y as a linear combination
+// of them.
+type Basis []func(x float64) float64
+
+// PolynomialBasis returns the basis 1, x, x^2, ..., x^degree.
+func PolynomialBasis(degree int) Basis {
+	b := make(Basis, degree+1)
+	for k := range b {
+		k := k
+		b[k] = func(x float64) float64 { return math.Pow(x, float64(k)) }
+	}
+	return b
+}
+
+// rankTolerance is how small a diagonal entry of R may be, relative to the
+// largest, before the basis is treated as linearly dependent on the data.
+const rankTolerance = 1e-10
+
+// BasisFit is the least squares fit of a linear combination of basis
+// functions to a dataset.
+type BasisFit struct {
+	Coefficients []float64 `json:"coefficients"` // one per basis function, in order
+	Fitted       []float64 `json:"fitted"`
+	Residuals    []float64 `json:"residuals"`
+	SSE          float64   `json:"sse"` // residual sum of squares
+	SST          float64   `json:"sst"` // total sum of squares of y about its mean
+	DF           int       `json:"df"`  // residual degrees of freedom, n minus the number of basis functions
+	basis        Basis
+	unscaled     []float64 // diagonal of the inverse of X'X, the coefficient variances per unit residual variance
+}
+
+// FitBasis fits y = sum of c[k]*b[k](x) by least squares, solving with a QR
+// decomposition of the design matrix rather than the normal equations so
+// that nearly collinear bases keep their accuracy. It returns ErrTooFew if
+// there are fewer observations than basis functions and ErrBounds if the basis
+// functions are linearly dependent at x, as a quadratic is when x takes only
+// two distinct values.
+func FitBasis(x, y []float64, b Basis) (BasisFit, error) {
+	if err := CheckDataQuality(x, y); err != nil {
+		return BasisFit{}, err
+	}
+	n, p := len(x), len(b)
+	if p == 0 || n < p {
+		return BasisFit{}, ErrTooFew
+	}
+	design := mat.NewDense(n, p, nil)
+	for i, v := range x {
+		for k, fn := range b {
+			design.Set(i, k, fn(v))
+		}
+	}
+	// Scale each column to unit length so that the rank test does not depend
+	// on the units of the basis functions, which for a polynomial in x of
+	// order 1e6 span a dozen orders of magnitude.
+	norms := make([]float64, p)
+	for k := range norms {
+		norms[k] = mat.Norm(design.ColView(k), 2)
+		if norms[k] == 0 {
+			return BasisFit{}, ErrBounds
+		}
+		for i := 0; i < n; i++ {
+			design.Set(i, k, design.At(i, k)/norms[k])
+		}
+	}
+	var qr mat.QR
+	qr.Factorize(design)
+	var r mat.Dense
+	qr.RTo(&r)
+	var largest float64
+	for k := 0; k < p; k++ {
+		largest = math.Max(largest, math.Abs(r.At(k, k)))
+	}
+	for k := 0; k < p; k++ {
+		if math.Abs(r.At(k, k)) <= rankTolerance*largest {
+			return BasisFit{}, ErrBounds
+		}
+	}
+	// X'X = R'R, so the diagonal of its inverse holds the squared row norms
+	// of the inverse of R, which is triangular and inverted directly.
+	tri := mat.NewTriDense(p, mat.Upper, nil)
+	for i := 0; i < p; i++ {
+		for j := i; j < p; j++ {
+			tri.SetTri(i, j, r.At(i, j))
+		}
+	}
+	if err := tri.InverseTri(tri); err != nil {
+		return BasisFit{}, ErrBounds
+	}
+	unscaled := make([]float64, p)
+	for i := range unscaled {
+		for j := i; j < p; j++ {
+			unscaled[i] += tri.At(i, j) * tri.At(i, j)
+		}
+		unscaled[i] /= norms[i] * norms[i]
+	}
+	var c mat.VecDense
+	if err := qr.SolveVecTo(&c, false, mat.NewVecDense(n, append([]float64(nil), y...))); err != nil {
+		return BasisFit{}, ErrBounds
+	}
+
+	fit := BasisFit{
+		Coefficients: make([]float64, p),
+		Fitted:       make([]float64, n),
+		Residuals:    make([]float64, n),
+		DF:           n - p,
+		basis:        b,
+		unscaled:     unscaled,
+	}
+	for k := range fit.Coefficients {
+		fit.Coefficients[k] = c.AtVec(k) / norms[k]
+	}
+	meanY, _ := Mean(y)
+	for i := range x {
+		fit.Fitted[i] = fit.Predict(x[i])
+		fit.Residuals[i] = y[i] - fit.Fitted[i]
+		fit.SSE += fit.Residuals[i] * fit.Residuals[i]
+		fit.SST += (y[i] - meanY) * (y[i] - meanY)
+	}
+	return fit, nil
+}
+
+// Predict returns the fitted value at x.
+func (f BasisFit) Predict(x float64) float64 {
+	var v float64
+	for k, fn := range f.basis {
+		v += f.Coefficients[k] * fn(x)
+	}
+	return v
+}
+
+// RSquared returns the coefficient of determination, 1 - SSE/SST.
+func (f BasisFit) RSquared() float64 {
+	return 1 - f.SSE/f.SST
+}
+
+// AdjustedRSquared returns R-squared penalized for the number of basis
+// functions, 1 - (SSE/DF) / (SST/(n-1)). It is NaN when DF is zero.
+func (f BasisFit) AdjustedRSquared() float64 {
+	if f.DF == 0 {
+		return math.NaN()
+	}
+	n := len(f.Residuals)
+	return 1 - (f.SSE/float64(f.DF))/(f.SST/float64(n-1))
+}
+
+// StdErrors returns the standard error of each coefficient, s*sqrt(v[k]),
+// where s is the residual standard error and v the diagonal of the inverse of
+// X'X for the design matrix X. They are NaN when DF is zero.
+func (f BasisFit) StdErrors() []float64 {
+	se := make([]float64, len(f.unscaled))
+	s := math.NaN()
+	if f.DF > 0 {
+		s = math.Sqrt(f.SSE / float64(f.DF))
+	}
+	for k, v := range f.unscaled {
+		se[k] = s * math.Sqrt(v)
+	}
+	return se
+}
+
+// NestedFTest tests whether full, whose basis extends that of reduced, fits
+// the same data significantly better. It returns the F statistic on
+// reduced.DF - full.DF and full.DF degrees of freedom and its p-value. It
+// returns ErrTooFew if full has no residual degrees of freedom and ErrBounds
+// if it has no more basis functions than reduced.
+func NestedFTest(reduced, full BasisFit) (f, p float64, err error) {
+	q := reduced.DF - full.DF
+	if full.DF < 1 {
+		return 0, 0, ErrTooFew
+	}
+	if q < 1 {
+		return 0, 0, ErrBounds
+	}
+	f = ((reduced.SSE - full.SSE) / float64(q)) / (full.SSE / float64(full.DF))
+	return f, distuv.F{D1: float64(q), D2: float64(full.DF)}.Survival(f), nil
+}
+
+// PolynomialFit compares a least squares polynomial with the straight line
+// fitted to the same dataset.
+type PolynomialFit struct {
+	Degree           int       `json:"degree"`
+	Coefficients     []float64 `json:"coefficients"` // constant term first
+	RSquared         Float     `json:"r_squared"`
+	AdjustedRSquared Float     `json:"adjusted_r_squared"`
+	F                Float     `json:"f"`               // nested F statistic of the polynomial against the line
+	FP               Float     `json:"f_p"`             // p-value of F
+	DF               int       `json:"df"`              // residual degrees of freedom of the polynomial
+	Error            string    `json:"error,omitempty"` // set instead of the other fields if the polynomial could not be fitted
+}
+
+// ComparePolynomial fits a polynomial of the given degree to x and y and
+// tests it against the straight line. A fit that fails is reported in Error.
+func ComparePolynomial(x, y []float64, degree int) PolynomialFit {
+	pf := PolynomialFit{Degree: degree}
+	line, err := FitBasis(x, y, PolynomialBasis(1))
+	if err != nil {
+		pf.Error = err.Error()
+		return pf
+	}
+	poly, err := FitBasis(x, y, PolynomialBasis(degree))
+	if err != nil {
+		pf.Error = err.Error()
+		return pf
+	}
+	f, p, err := NestedFTest(line, poly)
+	if err != nil {
+		pf.Error = err.Error()
+		return pf
+	}
+	pf.Coefficients = poly.Coefficients
+	pf.RSquared = Float(poly.RSquared())
+	pf.AdjustedRSquared = Float(poly.AdjustedRSquared())
+	pf.F, pf.FP = Float(f), Float(p)
+	pf.DF = poly.DF
+	return pf
+}
@@ -0,0 +1,106 @@
+package anscombe
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFitBasis(t *testing.T) {
+	// Test case 1: a cubic is recovered exactly
+	x := []float64{-2, -1, 0, 1, 2, 3}
+	y := make([]float64, len(x))
+	for i, v := range x {
+		y[i] = 1 - 2*v + 0.5*v*v*v
+	}
+	fit, err := FitBasis(x, y, PolynomialBasis(3))
+	if err != nil {
+		t.Fatalf("FitBasis() returned an error: %v", err)
+	}
+	for k, want := range []float64{1, -2, 0, 0.5} {
+		if math.Abs(fit.Coefficients[k]-want) > 1e-10 {
+			t.Errorf("Coefficient %d: expected %g, got %g", k, want, fit.Coefficients[k])
+		}
+	}
+	if fit.DF != 2 || fit.SSE > 1e-20 || math.Abs(fit.Predict(4)-25) > 1e-9 {
+		t.Errorf("FitBasis() = %+v, Predict(4) = %g", fit, fit.Predict(4))
+	}
+
+	// Test case 2: a straight line agrees with LinearRegression
+	d := Quartet()[0]
+	line, _ := FitBasis(d.X, d.Y, PolynomialBasis(1))
+	ols, _ := LinearRegression(d.X, d.Y)
+	if math.Abs(line.Coefficients[0]-ols.Intercept) > 1e-12 || math.Abs(line.Coefficients[1]-ols.Slope) > 1e-12 ||
+		math.Abs(line.SSE-ols.SSE) > 1e-12 || math.Abs(line.RSquared()-ols.RSquared()) > 1e-12 {
+		t.Errorf("FitBasis() of a line = %v, expected %g + %gx", line.Coefficients, ols.Intercept, ols.Slope)
+	}
+
+	// Test case 3: any basis, here y = a + b*sin(x)
+	x = []float64{0, 1, 2, 3, 4}
+	for i, v := range x {
+		y[i] = 2 + 3*math.Sin(v)
+	}
+	trig := Basis{func(float64) float64 { return 1 }, math.Sin}
+	if fit, err := FitBasis(x, y[:len(x)], trig); err != nil || math.Abs(fit.Coefficients[1]-3) > 1e-12 {
+		t.Errorf("FitBasis() with a sine basis = %v, %v", fit.Coefficients, err)
+	}
+}
+
+func TestFitBasisErrors(t *testing.T) {
+	// Test case 1: set 4 has only two distinct x, which cannot fix a quadratic
+	d := Quartet()[3]
+	if _, err := FitBasis(d.X, d.Y, PolynomialBasis(2)); err != ErrBounds {
+		t.Errorf("FitBasis() of a quadratic to set 4: expected %v, got %v", ErrBounds, err)
+	}
+
+	// Test case 2: fewer points than basis functions
+	if _, err := FitBasis([]float64{1, 2}, []float64{1, 2}, PolynomialBasis(2)); err != ErrTooFew {
+		t.Errorf("FitBasis() with two points: expected %v, got %v", ErrTooFew, err)
+	}
+
+	// Test case 3: an interpolating fit has no adjusted R-squared
+	fit, err := FitBasis([]float64{1, 2, 3}, []float64{1, 4, 2}, PolynomialBasis(2))
+	if err != nil || !math.IsNaN(fit.AdjustedRSquared()) {
+		t.Errorf("AdjustedRSquared() with no residual degrees of freedom = %g, %v", fit.AdjustedRSquared(), err)
+	}
+}
+
+func TestNestedFTest(t *testing.T) {
+	d := Quartet()[0]
+	// The line against a constant is the regression F statistic.
+	constant, _ := FitBasis(d.X, d.Y, PolynomialBasis(0))
+	line, _ := FitBasis(d.X, d.Y, PolynomialBasis(1))
+	fit, _ := LinearRegression(d.X, d.Y)
+	inf, _ := fit.Inference(0.95)
+	f, p, err := NestedFTest(constant, line)
+	if err != nil || math.Abs(f-float64(inf.F)) > 1e-9 || math.Abs(p-float64(inf.FP)) > 1e-12 {
+		t.Errorf("NestedFTest() = %g, %g, %v, expected %g, %g", f, p, err, inf.F, inf.FP)
+	}
+	if _, _, err := NestedFTest(line, constant); err != ErrBounds {
+		t.Errorf("NestedFTest() with the models swapped: expected %v, got %v", ErrBounds, err)
+	}
+}
+
+func TestComparePolynomial(t *testing.T) {
+	// Set 2 is a parabola: y = -5.996 + 2.781x - 0.1267x^2
+	d := Quartet()[1]
+	pf := ComparePolynomial(d.X, d.Y, 2)
+	if pf.Error != "" {
+		t.Fatalf("ComparePolynomial() returned an error: %s", pf.Error)
+	}
+	for k, want := range []float64{-5.996, 2.781, -0.1267} {
+		if math.Abs(pf.Coefficients[k]-want) > 0.001 {
+			t.Errorf("Coefficient %d: expected %g, got %g", k, want, pf.Coefficients[k])
+		}
+	}
+	if pf.AdjustedRSquared < 0.99999 || pf.AdjustedRSquared > pf.RSquared || pf.FP > 1e-10 || pf.DF != 8 {
+		t.Errorf("ComparePolynomial() of set 2 = %+v", pf)
+	}
+
+	// Set 1 gains nothing from the curvature.
+	if pf := ComparePolynomial(Quartet()[0].X, Quartet()[0].Y, 2); pf.FP < 0.4 {
+		t.Errorf("ComparePolynomial() of set 1 found curvature, p = %g", pf.FP)
+	}
+	if pf := ComparePolynomial(Quartet()[3].X, Quartet()[3].Y, 2); pf.Error != ErrBounds.Error() {
+		t.Errorf("ComparePolynomial() of set 4: expected error %q, got %+v", ErrBounds, pf)
+	}
+}
@@ -0,0 +1,250 @@
+package anscombe
+
+import (
+	"math"
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/montanaflynn/stats"
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// Resampling selects how bootstrap samples are drawn.
+type Resampling int
+
+const (
+	// PairsBootstrap resamples (x, y) pairs with replacement. It makes no
+	// assumption about the errors, but a resample may repeat a single x and
+	// leave the line undefined.
+	PairsBootstrap Resampling = iota
+	// ResidualBootstrap keeps x fixed and adds residuals of the least squares
+	// fit, resampled with replacement, to its fitted values. It assumes the
+	// errors are exchangeable.
+	ResidualBootstrap
+)
+
+// String returns "pairs" or "residual".
+func (r Resampling) String() string {
+	if r == ResidualBootstrap {
+		return "residual"
+	}
+	return "pairs"
+}
+
+// BootstrapOptions controls a bootstrap run.
+type BootstrapOptions struct {
+	Method     Resampling
+	Replicates int     // bootstrap samples drawn, 1999 if zero
+	Seed       int64   // seed from which every replicate derives its own generator
+	Workers    int     // goroutines drawing replicates, runtime.GOMAXPROCS(0) if zero
+	Level      float64 // confidence level of the intervals, 0.95 if zero
+}
+
+// bootstrapStatistics names the statistics estimated by Bootstrap, in order.
+var bootstrapStatistics = [...]string{"Intercept", "Slope", "R-squared", "Correlation"}
+
+// BootstrapInterval holds the bootstrap distribution summary of one statistic.
+type BootstrapInterval struct {
+	Statistic  string   `json:"statistic"`
+	Estimate   Float    `json:"estimate"`   // value on the original data, null where undefined
+	StdErr     Float    `json:"std_err"`    // standard deviation of the replicates
+	Percentile [2]Float `json:"percentile"` // percentile interval
+	BCa        [2]Float `json:"bca"`        // bias-corrected and accelerated interval, null where undefined
+}
+
+// BootstrapResult is the outcome of a bootstrap run.
+type BootstrapResult struct {
+	Method     string              `json:"method"`
+	Replicates int                 `json:"replicates"`
+	Failed     int                 `json:"failed"` // replicates on which the line was undefined, left out of the intervals
+	Seed       int64               `json:"seed"`
+	Level      float64             `json:"level"`
+	Intervals  []BootstrapInterval `json:"intervals"` // intercept, slope, R-squared and correlation
+}
+
+// Bootstrap estimates the sampling distributions of the intercept, slope,
+// R-squared and correlation of the least squares fit to x and y by
+// resampling, and returns percentile and BCa intervals for each. Replicate b
+// draws from its own generator derived from opts.Seed and b, so the result
+// is the same for a given seed whatever the number of workers. The BCa
+// acceleration is estimated by the jackknife, and the BCa interval is NaN if
+// a leave-one-out fit is undefined, as when dropping set 4's point at x = 19.
+func Bootstrap(x, y []float64, opts BootstrapOptions) (BootstrapResult, error) {
+	fit, err := LinearRegression(x, y)
+	if err != nil {
+		return BootstrapResult{}, err
+	}
+	estimate, err := fitStatistics(x, y)
+	if err != nil {
+		return BootstrapResult{}, err
+	}
+	replicates, workers, level := opts.Replicates, opts.Workers, opts.Level
+	if replicates == 0 {
+		replicates = 1999
+	}
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if level == 0 {
+		level = 0.95
+	}
+	if replicates < 0 || !(level > 0 && level < 1) {
+		return BootstrapResult{}, ErrBounds
+	}
+
+	values := make([][len(bootstrapStatistics)]float64, replicates)
+	ok := make([]bool, replicates)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			n := len(x)
+			bx, by := make([]float64, n), make([]float64, n)
+			for b := w; b < replicates; b += workers {
+				rng := rand.New(rand.NewSource(replicateSeed(opts.Seed, b)))
+				for i := range bx {
+					j := rng.Intn(n)
+					if opts.Method == ResidualBootstrap {
+						bx[i], by[i] = x[i], fit.Fitted[i]+fit.Residuals[j]
+					} else {
+						bx[i], by[i] = x[j], y[j]
+					}
+				}
+				v, err := replicateStatistics(bx, by)
+				values[b], ok[b] = v, err == nil
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	r := BootstrapResult{Method: opts.Method.String(), Replicates: replicates, Seed: opts.Seed, Level: level}
+	for _, good := range ok {
+		if !good {
+			r.Failed++
+		}
+	}
+	jackknife := jackknifeStatistics(x, y)
+	for k, name := range bootstrapStatistics {
+		var sample []float64
+		for b := range values {
+			if ok[b] {
+				sample = append(sample, values[b][k])
+			}
+		}
+		nan := Float(math.NaN())
+		in := BootstrapInterval{Statistic: name, Estimate: Float(estimate[k]), StdErr: nan}
+		in.Percentile, in.BCa = [2]Float{nan, nan}, [2]Float{nan, nan}
+		if len(sample) > 1 {
+			sort.Float64s(sample)
+			sd, _ := stats.StandardDeviationSample(sample)
+			in.StdErr = Float(sd)
+			alpha := (1 - level) / 2
+			in.Percentile = [2]Float{Float(stat.Quantile(alpha, stat.LinInterp, sample, nil)), Float(stat.Quantile(1-alpha, stat.LinInterp, sample, nil))}
+			in.BCa = bcaInterval(sample, estimate[k], jackknife[k], alpha)
+		}
+		r.Intervals = append(r.Intervals, in)
+	}
+	return r, nil
+}
+
+// fitStatistics returns the intercept, slope, R-squared and correlation of
+// the least squares fit to x and y. R-squared is NaN when y is constant.
+func fitStatistics(x, y []float64) ([len(bootstrapStatistics)]float64, error) {
+	fit, err := LinearRegression(x, y)
+	if err != nil {
+		return [len(bootstrapStatistics)]float64{}, err
+	}
+	r, err := stats.Correlation(x, y)
+	if err != nil {
+		return [len(bootstrapStatistics)]float64{}, err
+	}
+	return [len(bootstrapStatistics)]float64{fit.Intercept, fit.Slope, fit.RSquared(), r}, nil
+}
+
+// replicateStatistics returns the fitStatistics of a resample, or an error if
+// any of them is not finite, so that the resample counts as failed.
+func replicateStatistics(x, y []float64) ([len(bootstrapStatistics)]float64, error) {
+	v, err := fitStatistics(x, y)
+	if err != nil {
+		return v, err
+	}
+	for _, s := range v {
+		if math.IsNaN(s) {
+			return [len(bootstrapStatistics)]float64{}, ErrNaN
+		}
+		if math.IsInf(s, 0) {
+			return [len(bootstrapStatistics)]float64{}, ErrInfValue
+		}
+	}
+	return v, nil
+}
+
+// jackknifeStatistics returns, per statistic, its values with each point
+// left out in turn, or nil for every statistic if any leave-one-out fit is
+// undefined.
+func jackknifeStatistics(x, y []float64) [len(bootstrapStatistics)][]float64 {
+	var out [len(bootstrapStatistics)][]float64
+	n := len(x)
+	jx, jy := make([]float64, 0, n-1), make([]float64, 0, n-1)
+	for i := 0; i < n; i++ {
+		jx = append(append(jx[:0], x[:i]...), x[i+1:]...)
+		jy = append(append(jy[:0], y[:i]...), y[i+1:]...)
+		v, err := replicateStatistics(jx, jy)
+		if err != nil {
+			return [len(bootstrapStatistics)][]float64{}
+		}
+		for k := range out {
+			out[k] = append(out[k], v[k])
+		}
+	}
+	return out
+}
+
+// bcaInterval returns Efron's bias-corrected and accelerated interval from
+// the sorted replicates, the estimate on the original data and its jackknife
+// values. It is NaN where the bias correction or acceleration is undefined.
+func bcaInterval(sorted []float64, estimate float64, jackknife []float64, alpha float64) [2]Float {
+	nan := [2]Float{Float(math.NaN()), Float(math.NaN())}
+	if len(jackknife) == 0 {
+		return nan
+	}
+	below := sort.SearchFloat64s(sorted, estimate)
+	z0 := distuv.UnitNormal.Quantile(float64(below) / float64(len(sorted)))
+	if math.IsInf(z0, 0) || math.IsNaN(z0) {
+		return nan
+	}
+	mean, _ := Mean(jackknife)
+	var num, den float64
+	for _, v := range jackknife {
+		d := mean - v
+		num += d * d * d
+		den += d * d
+	}
+	var a float64
+	if den > 0 {
+		a = num / (6 * math.Pow(den, 1.5))
+	}
+	var out [2]Float
+	for i, p := range []float64{alpha, 1 - alpha} {
+		z := z0 + distuv.UnitNormal.Quantile(p)
+		adjusted := distuv.UnitNormal.CDF(z0 + z/(1-a*z))
+		if math.IsNaN(adjusted) {
+			return nan
+		}
+		out[i] = Float(stat.Quantile(adjusted, stat.LinInterp, sorted, nil))
+	}
+	return out
+}
+
+// replicateSeed derives the seed of replicate b from seed with the
+// SplitMix64 finalizer, so neighbouring replicates get unrelated streams.
+func replicateSeed(seed int64, b int) int64 {
+	z := uint64(seed) + uint64(b+1)*0x9e3779b97f4a7c15
+	z = (z ^ z>>30) * 0xbf58476d1ce4e5b9
+	z = (z ^ z>>27) * 0x94d049bb133111eb
+	return int64(z ^ z>>31)
+}
@@ -0,0 +1,117 @@
+package anscombe
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestBootstrapWorkers(t *testing.T) {
+	d := Quartet()[0]
+	for _, method := range []Resampling{PairsBootstrap, ResidualBootstrap} {
+		one, err := Bootstrap(d.X, d.Y, BootstrapOptions{Method: method, Replicates: 500, Seed: 3, Workers: 1})
+		if err != nil {
+			t.Fatalf("Bootstrap() returned an error: %v", err)
+		}
+		many, _ := Bootstrap(d.X, d.Y, BootstrapOptions{Method: method, Replicates: 500, Seed: 3, Workers: 7})
+		if !reflect.DeepEqual(one, many) {
+			t.Errorf("%v bootstrap with 1 and 7 workers differs:\n%+v\n%+v", method, one, many)
+		}
+		other, _ := Bootstrap(d.X, d.Y, BootstrapOptions{Method: method, Replicates: 500, Seed: 4, Workers: 7})
+		if reflect.DeepEqual(one.Intervals, other.Intervals) {
+			t.Errorf("%v bootstrap ignored the seed", method)
+		}
+	}
+}
+
+func TestBootstrapIntervals(t *testing.T) {
+	d := Quartet()[0]
+	fit, _ := LinearRegression(d.X, d.Y)
+	inf, _ := fit.Inference(0.95)
+	for _, method := range []Resampling{PairsBootstrap, ResidualBootstrap} {
+		b, err := Bootstrap(d.X, d.Y, BootstrapOptions{Method: method, Seed: 1})
+		if err != nil {
+			t.Fatalf("Bootstrap() returned an error: %v", err)
+		}
+		if b.Method != method.String() || b.Replicates != 1999 || b.Level != 0.95 || b.Failed != 0 {
+			t.Errorf("%v bootstrap: %+v", method, b)
+		}
+		slope := b.Intervals[1]
+		if slope.Statistic != "Slope" || float64(slope.Estimate) != fit.Slope {
+			t.Fatalf("Second interval is %+v, expected the slope", slope)
+		}
+		// The bootstrap standard error is close to the normal-theory one.
+		if math.Abs(float64(slope.StdErr)-inf.Slope.StdErr) > 0.03 {
+			t.Errorf("%v bootstrap std. error of the slope %g, normal theory %g", method, slope.StdErr, inf.Slope.StdErr)
+		}
+		for _, ci := range [][2]Float{slope.Percentile, slope.BCa} {
+			if !(float64(ci[0]) < fit.Slope && fit.Slope < float64(ci[1])) {
+				t.Errorf("%v bootstrap interval %v does not contain the slope %g", method, ci, fit.Slope)
+			}
+		}
+	}
+}
+
+func TestBootstrapSet4(t *testing.T) {
+	d := Quartet()[3]
+	// A pairs resample misses x = 19 with probability (10/11)^11, about 35%.
+	b, err := Bootstrap(d.X, d.Y, BootstrapOptions{Seed: 1})
+	if err != nil {
+		t.Fatalf("Bootstrap() returned an error: %v", err)
+	}
+	if rate := float64(b.Failed) / float64(b.Replicates); math.Abs(rate-math.Pow(10.0/11, 11)) > 0.05 {
+		t.Errorf("%d of %d replicates failed", b.Failed, b.Replicates)
+	}
+	// The jackknife cannot drop x = 19, so there is no BCa interval.
+	if in := b.Intervals[1]; !math.IsNaN(float64(in.BCa[0])) || math.IsNaN(float64(in.Percentile[0])) {
+		t.Errorf("Slope intervals of set 4: %+v", in)
+	}
+	// The residual bootstrap keeps every x and never fails.
+	if b, _ := Bootstrap(d.X, d.Y, BootstrapOptions{Method: ResidualBootstrap, Seed: 1}); b.Failed != 0 {
+		t.Errorf("%d residual bootstrap replicates failed", b.Failed)
+	}
+}
+
+func TestBootstrapConstantY(t *testing.T) {
+	// A pairs resample misses the last point, leaving y constant and R-squared
+	// undefined, with probability (5/6)^6, about 33%.
+	x, y := []float64{1, 2, 3, 4, 5, 6}, []float64{1, 1, 1, 1, 1, 5}
+	b, err := Bootstrap(x, y, BootstrapOptions{Seed: 1})
+	if err != nil {
+		t.Fatalf("Bootstrap() returned an error: %v", err)
+	}
+	if rate := float64(b.Failed) / float64(b.Replicates); math.Abs(rate-math.Pow(5.0/6, 6)) > 0.05 {
+		t.Errorf("%d of %d replicates failed", b.Failed, b.Replicates)
+	}
+	for _, in := range b.Intervals {
+		if math.IsNaN(float64(in.Percentile[0])) || math.IsNaN(float64(in.Percentile[1])) {
+			t.Errorf("Percentile interval of the %s: %v", in.Statistic, in.Percentile)
+		}
+	}
+	// With y constant throughout, R-squared is undefined on the data itself
+	// and every replicate fails.
+	b, err = Bootstrap(x, []float64{3, 3, 3, 3, 3, 3}, BootstrapOptions{Seed: 1})
+	if err != nil {
+		t.Fatalf("Bootstrap() of a constant y returned an error: %v", err)
+	}
+	if b.Failed != b.Replicates || !math.IsNaN(float64(b.Intervals[2].Estimate)) {
+		t.Errorf("Bootstrap() of a constant y: %d of %d replicates failed, R-squared %v", b.Failed, b.Replicates, b.Intervals[2].Estimate)
+	}
+}
+
+func TestBootstrapErrors(t *testing.T) {
+	d := Quartet()[0]
+	if _, err := Bootstrap(d.X, d.Y, BootstrapOptions{Level: 1}); !errors.Is(err, ErrBounds) {
+		t.Errorf("Bootstrap() at level 1: expected %v, got %v", ErrBounds, err)
+	}
+	if _, err := Bootstrap(d.X, d.Y, BootstrapOptions{Level: math.NaN()}); !errors.Is(err, ErrBounds) {
+		t.Errorf("Bootstrap() at level NaN: expected %v, got %v", ErrBounds, err)
+	}
+	if _, err := Bootstrap(d.X, d.Y[:3], BootstrapOptions{}); !errors.Is(err, ErrSize) {
+		t.Errorf("Bootstrap() of mismatched data: expected %v, got %v", ErrSize, err)
+	}
+	if ResidualBootstrap.String() != "residual" || PairsBootstrap.String() != "pairs" {
+		t.Error("Resampling.String() returned the wrong name")
+	}
+}
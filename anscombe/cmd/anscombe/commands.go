@@ -0,0 +1,370 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/bilguunbilegt/automated_programming/anscombe"
+	"github.com/bilguunbilegt/automated_programming/anscombe/plots"
+	"gonum.org/v1/plot/vg"
+)
+
+// failedSets reports that some sets could not be analyzed, after the output
+// for the others has been written.
+type failedSets []*anscombe.SetError
+
+func (e failedSets) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("%d of the sets could not be analyzed: %s", len(e), strings.Join(msgs, "; "))
+}
+
+// check returns the failures as a failedSets error, or nil if there are none.
+func check(failed []*anscombe.SetError) error {
+	if len(failed) == 0 {
+		return nil
+	}
+	return failedSets(failed)
+}
+
+// leanOptions are the options of the commands that need only the line and
+// the descriptive statistics: no robust or polynomial fits, association
+// measures or bootstrap.
+func leanOptions() anscombe.Options {
+	opts := anscombe.DefaultOptions()
+	opts.Robust = nil
+	opts.Degree = 0
+	opts.Measures = nil
+	opts.Permutations = 0
+	opts.Replicates = 0
+	return opts
+}
+
+// options returns opts with the missing-value policy of --missing.
+func (c *config) options(opts anscombe.Options) anscombe.Options {
+	opts.Missing = c.missing
+	return opts
+}
+
+// described is the output of describe for one set.
+type described struct {
+	Name    string                `json:"name"`
+	N       int                   `json:"n"`
+	Missing *anscombe.Missing     `json:"missing,omitempty"`
+	X       *anscombe.Description `json:"x,omitempty"`
+	Y       *anscombe.Description `json:"y,omitempty"`
+	Error   string                `json:"error,omitempty"`
+}
+
+func describe(c *config, datasets []anscombe.Dataset) error {
+	var out []described
+	var failed []*anscombe.SetError
+	for _, d := range datasets {
+		d, missing := c.missing.Apply(d)
+		e := described{Name: d.Name, N: d.Len()}
+		if missing.Missing > 0 {
+			e.Missing = &missing
+		}
+		// The variables need not pair up, but must be free of NaN
+		err := anscombe.Check(d, []anscombe.Rule{anscombe.NonFinite{}}).Err()
+		x, errX := anscombe.Describe(d.X, anscombe.Sample)
+		y, errY := anscombe.Describe(d.Y, anscombe.Sample)
+		if err == nil {
+			err = errX
+		}
+		if err == nil {
+			err = errY
+		}
+		if err != nil {
+			e.Error = err.Error()
+			failed = append(failed, &anscombe.SetError{Set: d.Name, Err: err})
+		} else {
+			e.X, e.Y = &x, &y
+		}
+		out = append(out, e)
+	}
+	err := c.write(func(w io.Writer) error {
+		if c.format == "json" {
+			return writeJSON(w, struct {
+				Sets []described `json:"sets"`
+			}{out}, c.precisionOf())
+		}
+		f := c.precisionOf().Format
+		bw := bufio.NewWriter(w)
+		for i, e := range out {
+			if i > 0 {
+				fmt.Fprintln(bw)
+			}
+			fmt.Fprintf(bw, "%s\n  Observations: %d\n", e.Name, e.N)
+			writeMissing(bw, e.Missing)
+			if e.Error != "" {
+				fmt.Fprintf(bw, "  Error: %s\n", e.Error)
+				continue
+			}
+			for _, v := range []struct {
+				name string
+				d    *anscombe.Description
+			}{{"X", e.X}, {"Y", e.Y}} {
+				fmt.Fprintf(bw, "  %s: mean %s, variance %s, std. dev. %s [%v]\n", v.name, f(v.d.Mean), f(v.d.Variance), f(v.d.StdDev), v.d.DDOF)
+				fmt.Fprintf(bw, "  %s: median %s, quartiles [%s, %s], IQR %s, range [%s, %s], MAD %s\n", v.name,
+					f(v.d.Median), f(v.d.Q1), f(v.d.Q3), f(v.d.IQR), f(v.d.Min), f(v.d.Max), f(v.d.MAD))
+				fmt.Fprintf(bw, "  %s: skewness %s, excess kurtosis %s, CV %s\n", v.name,
+					f(float64(v.d.Skewness)), f(float64(v.d.ExcessKurtosis)), f(float64(v.d.CV)))
+			}
+		}
+		return bw.Flush()
+	})
+	if err != nil {
+		return err
+	}
+	return check(failed)
+}
+
+// fitted is the output of fit for one set.
+type fitted struct {
+	Name      string              `json:"name"`
+	N         int                 `json:"n"`
+	Missing   *anscombe.Missing   `json:"missing,omitempty"`
+	Fit       *anscombe.Fit       `json:"fit,omitempty"`
+	RSquared  anscombe.Float      `json:"r_squared"`
+	Inference *anscombe.Inference `json:"inference,omitempty"` // nil with fewer than three points
+	Error     string              `json:"error,omitempty"`
+}
+
+func fit(c *config, datasets []anscombe.Dataset) error {
+	var out []fitted
+	var failed []*anscombe.SetError
+	for _, d := range datasets {
+		e := fitted{Name: d.Name, N: d.Len()}
+		s, err := anscombe.Summarize(d, c.options(leanOptions()))
+		if err != nil {
+			e.Error = err.Error()
+			failed = append(failed, &anscombe.SetError{Set: d.Name, Err: err})
+		} else {
+			e.N, e.Missing = s.N, s.Missing
+			e.Fit, e.RSquared = &s.Fit, s.RSquared
+			if s.Inference.Level != 0 {
+				e.Inference = &s.Inference
+			}
+		}
+		out = append(out, e)
+	}
+	err := c.write(func(w io.Writer) error {
+		if c.format == "json" {
+			return writeJSON(w, struct {
+				Sets []fitted `json:"sets"`
+			}{out}, c.precisionOf())
+		}
+		f := c.precisionOf().Format
+		bw := bufio.NewWriter(w)
+		for i, e := range out {
+			if i > 0 {
+				fmt.Fprintln(bw)
+			}
+			fmt.Fprintf(bw, "%s\n  Observations: %d\n", e.Name, e.N)
+			writeMissing(bw, e.Missing)
+			if e.Error != "" {
+				fmt.Fprintf(bw, "  Error: %s\n", e.Error)
+				continue
+			}
+			fmt.Fprintf(bw, "  Line: y = %s + %s x\n", f(e.Fit.Intercept), f(e.Fit.Slope))
+			fmt.Fprintf(bw, "  R-squared: %s\n", f(float64(e.RSquared)))
+			if inf := e.Inference; inf != nil {
+				for _, v := range []struct {
+					name string
+					c    anscombe.Coefficient
+				}{{"Intercept", inf.Intercept}, {"Slope", inf.Slope}} {
+					fmt.Fprintf(bw, "  %s: %s (std. error %s, t %s, p %s, %g%% CI [%s, %s])\n", v.name,
+						f(v.c.Estimate), f(v.c.StdErr), f(float64(v.c.T)), f(float64(v.c.P)), inf.Level*100, f(v.c.Lower), f(v.c.Upper))
+				}
+				fmt.Fprintf(bw, "  Residual std. error: %s on %d degrees of freedom\n", f(inf.ResidualStdErr), e.Fit.DF)
+				fmt.Fprintf(bw, "  F-statistic: %s on 1 and %d DF, p %s\n", f(float64(inf.F)), e.Fit.DF, f(float64(inf.FP)))
+			}
+		}
+		return bw.Flush()
+	})
+	if err != nil {
+		return err
+	}
+	return check(failed)
+}
+
+func plotSets(c *config, datasets []anscombe.Dataset) error {
+	var drawn []anscombe.Dataset
+	var failed []*anscombe.SetError
+	for _, d := range datasets {
+		d, _ := c.missing.Apply(d)
+		p, err := plots.Scatter(d, plots.DefaultScatterOptions())
+		if err != nil {
+			failed = append(failed, &anscombe.SetError{Set: d.Name, Err: err})
+			continue
+		}
+		p.Title.Text = d.Name
+		path, err := c.path(d.Slug() + "." + c.format)
+		if err != nil {
+			return err
+		}
+		if err := p.Save(5*vg.Inch, 5*vg.Inch, path); err != nil {
+			return ioError{err}
+		}
+		panels, err := plots.Diagnostics(d)
+		if err != nil {
+			failed = append(failed, &anscombe.SetError{Set: d.Name, Err: err})
+			continue
+		}
+		if path, err = c.path(d.Slug() + "_diagnostics." + c.format); err != nil {
+			return err
+		}
+		if err := plots.SaveFacet(panels, 8*vg.Inch, 8*vg.Inch, path); err != nil {
+			return ioError{err}
+		}
+		drawn = append(drawn, d)
+	}
+	if len(drawn) > 0 {
+		panels, err := plots.Facet(drawn, plots.DefaultFacetOptions())
+		if err != nil {
+			return err
+		}
+		path, err := c.path("anscombe." + c.format)
+		if err != nil {
+			return err
+		}
+		if err := plots.SaveFacet(panels, 8*vg.Inch, 8*vg.Inch, path); err != nil {
+			return ioError{err}
+		}
+	}
+	return check(failed)
+}
+
+func report(c *config, datasets []anscombe.Dataset) error {
+	results := anscombe.Analyze(datasets, c.options(anscombe.DefaultOptions()))
+	err := c.write(func(w io.Writer) error {
+		return anscombe.WriteReport(w, results, anscombe.Format(c.format), c.precisionOf())
+	})
+	if err != nil {
+		return err
+	}
+	return check(results.Failed())
+}
+
+// statistic is one row of the compare table.
+type statistic struct {
+	Name   string           `json:"name"`
+	Values []anscombe.Float `json:"values"` // one per set, in the order of Comparison.Sets, null where undefined
+	Spread anscombe.Float   `json:"spread"` // largest value less the smallest
+}
+
+// comparison is the output of compare.
+type comparison struct {
+	Sets       []string          `json:"sets"`
+	Statistics []statistic       `json:"statistics"`
+	Errors     map[string]string `json:"errors,omitempty"` // sets that could not be analyzed, left out of Sets
+}
+
+// compared are the rows of the compare table, the statistics Anscombe chose
+// to be identical across the quartet.
+var compared = []struct {
+	name, label string
+	value       func(s anscombe.Summary) float64
+}{
+	{"mean_x", "Mean X", func(s anscombe.Summary) float64 { return s.MeanX }},
+	{"mean_y", "Mean Y", func(s anscombe.Summary) float64 { return s.MeanY }},
+	{"variance_x", "Variance X", func(s anscombe.Summary) float64 { return s.VarianceX }},
+	{"variance_y", "Variance Y", func(s anscombe.Summary) float64 { return s.VarianceY }},
+	{"correlation", "Correlation", func(s anscombe.Summary) float64 { return float64(s.Correlation) }},
+	{"intercept", "Intercept", func(s anscombe.Summary) float64 { return s.Fit.Intercept }},
+	{"slope", "Slope", func(s anscombe.Summary) float64 { return s.Fit.Slope }},
+	{"r_squared", "R-squared", func(s anscombe.Summary) float64 { return float64(s.RSquared) }},
+	{"residual_std_err", "Residual std. error", func(s anscombe.Summary) float64 { return s.Inference.ResidualStdErr }},
+}
+
+func compare(c *config, datasets []anscombe.Dataset) error {
+	var summaries []anscombe.Summary
+	out := comparison{}
+	var failed []*anscombe.SetError
+	for _, d := range datasets {
+		s, err := anscombe.Summarize(d, c.options(leanOptions()))
+		if err != nil {
+			if out.Errors == nil {
+				out.Errors = map[string]string{}
+			}
+			out.Errors[d.Name] = err.Error()
+			failed = append(failed, &anscombe.SetError{Set: d.Name, Err: err})
+			continue
+		}
+		summaries = append(summaries, s)
+		out.Sets = append(out.Sets, s.Name)
+	}
+	for _, row := range compared {
+		st := statistic{Name: row.name}
+		lo, hi := math.Inf(1), math.Inf(-1)
+		for _, s := range summaries {
+			v := row.value(s)
+			st.Values = append(st.Values, anscombe.Float(v))
+			lo, hi = math.Min(lo, v), math.Max(hi, v)
+		}
+		if len(summaries) > 0 {
+			st.Spread = anscombe.Float(hi - lo)
+		}
+		out.Statistics = append(out.Statistics, st)
+	}
+
+	err := c.write(func(w io.Writer) error {
+		if c.format == "json" {
+			return writeJSON(w, out, c.precisionOf())
+		}
+		f := c.precisionOf().Format
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprint(tw, "Statistic")
+		for _, name := range out.Sets {
+			fmt.Fprintf(tw, "\t%s", name)
+		}
+		fmt.Fprintln(tw, "\tSpread")
+		for i, st := range out.Statistics {
+			fmt.Fprint(tw, compared[i].label)
+			for _, v := range st.Values {
+				fmt.Fprintf(tw, "\t%s", f(float64(v)))
+			}
+			fmt.Fprintf(tw, "\t%s\n", f(float64(st.Spread)))
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+		for _, e := range failed {
+			fmt.Fprintf(w, "%s: Error: %v\n", e.Set, e.Err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return check(failed)
+}
+
+// writeMissing writes what the missing-value policy did to a set, if it had
+// missing values.
+func writeMissing(w io.Writer, m *anscombe.Missing) {
+	if m != nil {
+		fmt.Fprintf(w, "  Missing x or y: %d, handled by %s: %d dropped, %d imputed\n", m.Missing, m.Policy, m.Dropped, m.Imputed)
+	}
+}
+
+// writeJSON writes v as indented JSON with every number rounded to p.
+func writeJSON[T any](w io.Writer, v T, p anscombe.Precision) error {
+	if p.Digits > 0 {
+		var err error
+		if v, err = anscombe.Rounded(v, p); err != nil {
+			return err
+		}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -0,0 +1,379 @@
+// Command anscombe analyzes paired datasets such as Anscombe's quartet from
+// the command line. Run "anscombe help" for its subcommands, flags and exit
+// statuses.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/bilguunbilegt/automated_programming/anscombe"
+)
+
+// Exit statuses, so that scripts can tell bad data from a failed file.
+const (
+	exitOK    = 0
+	exitData  = 1 // the input could not be parsed, or a set could not be analyzed
+	exitUsage = 2 // the command line is wrong
+	exitIO    = 3 // a file could not be opened, read or written
+)
+
+// command is a subcommand of anscombe.
+type command struct {
+	name    string
+	summary string
+	formats []string // accepted values of --format, the first being the default
+	output  string   // base name of the file written under --out-dir
+	run     func(c *config, datasets []anscombe.Dataset) error
+}
+
+var (
+	reportFormats = []string{"text", "json"}
+	imageFormats  = []string{"png", "svg", "pdf", "eps", "jpg", "tif"}
+)
+
+var commands = []command{
+	{"describe", "count, mean, quartiles, variance, skewness and kurtosis of x and y", reportFormats, "describe", describe},
+	{"fit", "least squares line with standard errors, t tests and confidence intervals", reportFormats, "fit", fit},
+	{"plot", "scatter plot, diagnostic panels and a side-by-side figure of the sets", imageFormats, "", plotSets},
+	{"report", "the full analysis, as written to results.txt and results.json", reportFormats, "results", report},
+	{"compare", "the headline statistics of every set side by side, with their spread", reportFormats, "compare", compare},
+}
+
+// config holds the flags shared by every subcommand and where it reads and
+// writes.
+type config struct {
+	cmd       command
+	input     string
+	format    string
+	outDir    string
+	precision precisionFlag
+	sets      string
+	missing   anscombe.MissingPolicy
+	stdin     io.Reader
+	stdout    io.Writer
+}
+
+// precisionFlag is the value of --precision, which defaults to the precision
+// of the output format rather than to a number.
+type precisionFlag struct {
+	set    bool
+	digits int
+}
+
+func (p *precisionFlag) String() string {
+	if p == nil || !p.set {
+		return ""
+	}
+	return strconv.Itoa(p.digits)
+}
+
+func (p *precisionFlag) Set(s string) error {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return errors.New("not a number")
+	}
+	if n < 0 {
+		return errors.New("must not be negative")
+	}
+	p.set, p.digits = true, n
+	return nil
+}
+
+// usageError is a mistake in the command line.
+type usageError struct {
+	msg string
+}
+
+func (e usageError) Error() string {
+	return e.msg
+}
+
+// ioError is a failure to write output.
+type ioError struct {
+	err error
+}
+
+func (e ioError) Error() string {
+	return e.err.Error()
+}
+
+func (e ioError) Unwrap() error {
+	return e.err
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the command line args and returns the exit status.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	err := dispatch(args, stdin, stdout)
+	if err == nil {
+		return exitOK
+	}
+	fmt.Fprintf(stderr, "anscombe: %v\n", err)
+	code := exitCode(err)
+	if code == exitUsage {
+		fmt.Fprintln(stderr, "Run 'anscombe help' for usage.")
+	}
+	return code
+}
+
+// exitCode maps an error to the exit status it is reported with.
+func exitCode(err error) int {
+	var usage usageError
+	var out ioError
+	var path *fs.PathError
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.As(err, &usage):
+		return exitUsage
+	case errors.As(err, &out), errors.As(err, &path):
+		return exitIO
+	}
+	return exitData
+}
+
+func dispatch(args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) == 0 {
+		return usageError{"no command given"}
+	}
+	switch args[0] {
+	case "help", "-h", "-help", "--help":
+		if len(args) > 1 {
+			cmd, ok := lookupCommand(args[1])
+			if !ok {
+				return usageError{fmt.Sprintf("unknown command %q", args[1])}
+			}
+			return writeCommandUsage(stdout, cmd, newFlagSet(cmd, &config{}))
+		}
+		return writeUsage(stdout)
+	}
+	cmd, ok := lookupCommand(args[0])
+	if !ok {
+		return usageError{fmt.Sprintf("unknown command %q", args[0])}
+	}
+
+	c := &config{cmd: cmd, stdin: stdin, stdout: stdout}
+	fs := newFlagSet(cmd, c)
+	if err := fs.Parse(args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return writeCommandUsage(stdout, cmd, fs)
+		}
+		return usageError{fmt.Sprintf("%s: %v", cmd.name, err)}
+	}
+	if fs.NArg() > 0 {
+		return usageError{fmt.Sprintf("%s: unexpected argument %q", cmd.name, fs.Arg(0))}
+	}
+	if c.format == "" {
+		c.format = cmd.formats[0]
+	}
+	if !contains(cmd.formats, c.format) {
+		return usageError{fmt.Sprintf("%s: --format must be one of %s, not %q", cmd.name, strings.Join(cmd.formats, ", "), c.format)}
+	}
+
+	datasets, err := c.load()
+	if err != nil {
+		return err
+	}
+	if datasets, err = selectSets(datasets, c.sets); err != nil {
+		return err
+	}
+	return cmd.run(c, datasets)
+}
+
+func lookupCommand(name string) (command, bool) {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
+
+func newFlagSet(cmd command, c *config) *flag.FlagSet {
+	fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Usage = func() {}
+	fs.StringVar(&c.input, "input", "", "`file` of datasets: CSV or TSV with dataset, x and y columns, JSON or NDJSON; - reads CSV from standard input; the built-in quartet if empty")
+	fs.StringVar(&c.format, "format", "", "output `format`: "+strings.Join(cmd.formats, ", ")+" (default "+cmd.formats[0]+")")
+	outDir := "standard output if empty"
+	if cmd.output == "" {
+		outDir = "the current directory if empty"
+	}
+	fs.StringVar(&c.outDir, "out-dir", "", "`directory` to write into, created if missing; "+outDir)
+	fs.Var(&c.precision, "precision", "significant `digits` of numbers in text and JSON output; 0 keeps full precision (default 3 for text, full for JSON)")
+	fs.TextVar(&c.missing, "missing", anscombe.FailMissing, "`policy` for observations with a blank or NaN x or y: fail, drop (the observation), mean or median (of the variable), or interpolate (y along x)")
+	fs.StringVar(&c.sets, "sets", "", "comma-separated `names` of the sets to analyze, as given or as in file names (set_1); all if empty")
+	return fs
+}
+
+func writeUsage(w io.Writer) error {
+	fmt.Fprintln(w, "Usage: anscombe <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Analyze paired (x, y) datasets, Anscombe's quartet unless --input is given.")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-9s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Every command takes --input, --format, --out-dir, --precision, --missing and --sets.")
+	fmt.Fprintln(w, "Run 'anscombe help <command>' for details.")
+	fmt.Fprintln(w)
+	return writeExitStatus(w)
+}
+
+func writeCommandUsage(w io.Writer, cmd command, fs *flag.FlagSet) error {
+	fmt.Fprintf(w, "Usage: anscombe %s [flags]\n\n", cmd.name)
+	fmt.Fprintf(w, "%s%s.\n", strings.ToUpper(cmd.summary[:1]), cmd.summary[1:])
+	if cmd.output != "" {
+		fmt.Fprintf(w, "With --out-dir the output goes to %s.txt or %s.json there.\n", cmd.output, cmd.output)
+	} else {
+		fmt.Fprintln(w, "Each set is drawn to <set>.<format> and <set>_diagnostics.<format>, and all of them to anscombe.<format>.")
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Flags:")
+	fs.SetOutput(w)
+	fs.PrintDefaults()
+	fs.SetOutput(io.Discard)
+	fmt.Fprintln(w)
+	return writeExitStatus(w)
+}
+
+func writeExitStatus(w io.Writer) error {
+	_, err := fmt.Fprintf(w, `Exit status:
+  %d  success
+  %d  data error: the input could not be parsed or a set could not be analyzed
+  %d  usage error: unknown command, flag or format
+  %d  I/O error: a file could not be opened, read or written
+`, exitOK, exitData, exitUsage, exitIO)
+	return err
+}
+
+// load reads the datasets named by --input.
+func (c *config) load() ([]anscombe.Dataset, error) {
+	opts := anscombe.CSVOptions{GroupColumn: "dataset"}
+	switch c.input {
+	case "":
+		return anscombe.Quartet(), nil
+	case "-":
+		datasets, err := anscombe.ReadCSV(c.stdin, opts)
+		if err != nil {
+			return nil, fmt.Errorf("standard input: %w", err)
+		}
+		return datasets, nil
+	}
+	datasets, err := anscombe.LoadFile(c.input, opts)
+	var path *fs.PathError
+	if err != nil && !errors.As(err, &path) {
+		return nil, fmt.Errorf("%s: %w", c.input, err)
+	}
+	return datasets, err
+}
+
+// selectSets returns the datasets named in the comma-separated list names,
+// in its order, matching either the name or its slug, or all of them if
+// names is empty. A name that matches no set is a usageError.
+func selectSets(datasets []anscombe.Dataset, names string) ([]anscombe.Dataset, error) {
+	if names == "" {
+		return datasets, nil
+	}
+	var selected []anscombe.Dataset
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		found := false
+		for _, d := range datasets {
+			if d.Name == name || d.Slug() == name {
+				selected = append(selected, d)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, usageError{fmt.Sprintf("--sets: no set named %q in the input", name)}
+		}
+	}
+	return selected, nil
+}
+
+// precisionOf returns the precision of --precision, or the default of the
+// output format.
+func (c *config) precisionOf() anscombe.Precision {
+	if !c.precision.set {
+		return anscombe.DefaultPrecision(anscombe.Format(c.format))
+	}
+	return anscombe.Precision{Digits: c.precision.digits, Significant: true}
+}
+
+// write renders emit in memory and copies it to standard output, or to the
+// command's file under --out-dir, named after the format. An error of emit is
+// returned as is, leaving no file behind; only failing to write the output is
+// an ioError, and removes the partial file.
+func (c *config) write(emit func(w io.Writer) error) error {
+	var buf bytes.Buffer
+	if err := emit(&buf); err != nil {
+		return err
+	}
+	if c.outDir == "" {
+		if _, err := buf.WriteTo(c.stdout); err != nil {
+			return ioError{err}
+		}
+		return nil
+	}
+	path, err := c.path(c.cmd.output + "." + extension(c.format))
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	_, err = buf.WriteTo(f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(path)
+		return ioError{err}
+	}
+	return nil
+}
+
+// path returns name joined to --out-dir, creating the directory if needed.
+func (c *config) path(name string) (string, error) {
+	dir := c.outDir
+	if dir == "" {
+		dir = "."
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name), nil
+}
+
+func extension(format string) string {
+	if format == "text" {
+		return "txt"
+	}
+	return format
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// runArgs runs the command line and returns its exit status, standard output
+// and standard error.
+func runArgs(t *testing.T, stdin string, args ...string) (int, string, string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestExitCodes(t *testing.T) {
+	dir := t.TempDir()
+	bad := filepath.Join(dir, "bad.csv")
+	if err := os.WriteFile(bad, []byte("dataset,x,y\nA,1,oops\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(dir, "file")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"success", []string{"fit"}, exitOK},
+		{"help", []string{"help", "compare"}, exitOK},
+		{"flag help", []string{"describe", "-h"}, exitOK},
+		{"no command", nil, exitUsage},
+		{"unknown command", []string{"regress"}, exitUsage},
+		{"unknown flag", []string{"fit", "--colour"}, exitUsage},
+		{"bad format", []string{"describe", "--format", "png"}, exitUsage},
+		{"negative precision", []string{"fit", "--precision", "-2"}, exitUsage},
+		{"stray argument", []string{"fit", "data.csv"}, exitUsage},
+		{"unparsable value", []string{"fit", "--input", bad}, exitData},
+		{"unknown set", []string{"fit", "--sets", "Set 9"}, exitUsage},
+		{"missing input", []string{"fit", "--input", filepath.Join(dir, "missing.csv")}, exitIO},
+		{"output under a file", []string{"fit", "--out-dir", filepath.Join(file, "out")}, exitIO},
+	}
+	for _, tt := range tests {
+		if code, _, stderr := runArgs(t, "", tt.args...); code != tt.want {
+			t.Errorf("%s: exit status %d, expected %d (%s)", tt.name, code, tt.want, stderr)
+		}
+	}
+}
+
+func TestFailedSet(t *testing.T) {
+	// Test case 1: the flat set fails but the good one is still written
+	input := "dataset,x,y\nGood,1,1\nGood,2,3\nGood,3,2\nGood,4,5\nFlat,2,1\nFlat,2,2\nFlat,2,4\n"
+	code, stdout, stderr := runArgs(t, input, "fit", "--input", "-", "--format", "json")
+	if code != exitData || !strings.Contains(stderr, "Flat: x: Values must not all be equal.") {
+		t.Errorf("fit with a flat set: exit status %d, stderr %q", code, stderr)
+	}
+	var doc struct {
+		Sets []fitted `json:"sets"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &doc); err != nil {
+		t.Fatalf("fit --format json wrote invalid JSON: %v", err)
+	}
+	if len(doc.Sets) != 2 || doc.Sets[0].Fit == nil || doc.Sets[1].Error == "" {
+		t.Errorf("fit --format json = %s", stdout)
+	}
+
+	// Test case 2: describe needs no line, so the flat set succeeds
+	if code, _, stderr := runArgs(t, input, "describe", "--input", "-"); code != exitOK {
+		t.Errorf("describe with a flat set: exit status %d, stderr %q", code, stderr)
+	}
+}
+
+func TestMissing(t *testing.T) {
+	input := "dataset,x,y\nA,1,2\nA,2,\nA,3,6\nA,4,9\nA,,10\n"
+	// Test case 1: by default a missing value fails the set
+	if code, _, stderr := runArgs(t, input, "fit", "--input", "-"); code != exitData || !strings.Contains(stderr, "A: x[4] = NaN: Not a number.; y[1] = NaN: Not a number.") {
+		t.Errorf("fit with a blank y: exit status %d, stderr %q", code, stderr)
+	}
+
+	// Test case 2: dropping fits the three complete observations and says so
+	code, stdout, stderr := runArgs(t, input, "fit", "--input", "-", "--missing", "drop", "--format", "json")
+	if code != exitOK {
+		t.Fatalf("fit --missing drop: exit status %d, stderr %q", code, stderr)
+	}
+	var doc struct {
+		Sets []fitted `json:"sets"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &doc); err != nil {
+		t.Fatalf("fit --format json wrote invalid JSON: %v", err)
+	}
+	if s := doc.Sets[0]; s.N != 3 || s.Missing == nil || s.Missing.Dropped != 2 || s.Missing.Imputed != 0 {
+		t.Errorf("fit --missing drop = %s", stdout)
+	}
+
+	// Test case 3: the report states what interpolation did
+	_, stdout, _ = runArgs(t, input, "report", "--input", "-", "--missing", "interpolate")
+	if !strings.Contains(stdout, "Missing x or y: 2, handled by interpolate: 1 dropped, 1 imputed") {
+		t.Errorf("report --missing interpolate wrote:\n%s", stdout)
+	}
+
+	// Test case 4: an unknown policy is a usage error
+	if code, _, _ := runArgs(t, input, "fit", "--missing", "zero"); code != exitUsage {
+		t.Errorf("fit --missing zero: exit status %d, expected %d", code, exitUsage)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	code, stdout, _ := runArgs(t, "", "compare", "--format", "json")
+	if code != exitOK {
+		t.Fatalf("compare: exit status %d", code)
+	}
+	var doc comparison
+	if err := json.Unmarshal([]byte(stdout), &doc); err != nil {
+		t.Fatalf("compare --format json wrote invalid JSON: %v", err)
+	}
+	if len(doc.Sets) != 4 || len(doc.Statistics) != len(compared) {
+		t.Fatalf("compare = %+v", doc)
+	}
+	// The quartet's headline statistics agree to two or three decimals
+	for _, st := range doc.Statistics {
+		if st.Spread > 0.01 {
+			t.Errorf("Spread of %s across the quartet = %g", st.Name, st.Spread)
+		}
+	}
+}
+
+func TestSetsAndPrecision(t *testing.T) {
+	// Test case 1: sets are selected by name or slug, in the order given
+	code, stdout, _ := runArgs(t, "", "fit", "--sets", "set_4, Set 2", "--precision", "2")
+	if code != exitOK {
+		t.Fatalf("fit --sets: exit status %d", code)
+	}
+	if i, j := strings.Index(stdout, "Set 4"), strings.Index(stdout, "Set 2"); i < 0 || j < i || strings.Contains(stdout, "Set 1") {
+		t.Errorf("fit --sets set_4,Set 2 wrote:\n%s", stdout)
+	}
+	if !strings.Contains(stdout, "Line: y = 3.0 + 0.50 x") {
+		t.Errorf("fit --precision 2 wrote:\n%s", stdout)
+	}
+
+	// Test case 2: JSON keeps full precision unless asked
+	_, stdout, _ = runArgs(t, "", "describe", "--format", "json", "--sets", "Set 1")
+	var doc struct {
+		Sets []described `json:"sets"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &doc); err != nil {
+		t.Fatalf("describe --format json wrote invalid JSON: %v", err)
+	}
+	if got := doc.Sets[0].Y.Mean; math.Abs(got-7.500909090909091) > 1e-12 {
+		t.Errorf("Mean Y of set 1 = %v, expected full precision", got)
+	}
+}
+
+func TestOutDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "out")
+
+	// Test case 1: a report goes to results.json in a new directory
+	if code, stdout, stderr := runArgs(t, "", "report", "--format", "json", "--sets", "Set 1", "--out-dir", dir); code != exitOK || stdout != "" {
+		t.Fatalf("report --out-dir: exit status %d, stdout %q, stderr %q", code, stdout, stderr)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "results.json")); err != nil || !strings.Contains(string(data), `"name": "Set 1"`) {
+		t.Errorf("results.json: %v", err)
+	}
+
+	// Test case 2: plots are named after the sets and the format
+	if code, _, stderr := runArgs(t, "", "plot", "--format", "svg", "--sets", "Set 2", "--out-dir", dir); code != exitOK {
+		t.Fatalf("plot: exit status %d, stderr %q", code, stderr)
+	}
+	for _, name := range []string{"set_2.svg", "set_2_diagnostics.svg", "anscombe.svg"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("plot did not write %s: %v", name, err)
+		}
+	}
+}
+
+// failingWriter fails every write.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestWrite(t *testing.T) {
+	dir := t.TempDir()
+	c := &config{cmd: commands[0], format: "json", outDir: dir}
+	path := filepath.Join(dir, "describe.json")
+
+	// Test case 1: an output that cannot be encoded is bad data and leaves no file
+	err := c.write(func(w io.Writer) error {
+		return json.NewEncoder(w).Encode(math.NaN())
+	})
+	if err == nil || exitCode(err) != exitData {
+		t.Errorf("write() of NaN: exit status %d, expected %d (%v)", exitCode(err), exitData, err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("write() of NaN left %s behind: %v", path, err)
+	}
+
+	// Test case 2: failing to write standard output is an I/O error
+	c = &config{cmd: commands[0], format: "text", stdout: failingWriter{}}
+	err = c.write(func(w io.Writer) error {
+		_, err := io.WriteString(w, "Set 1\n")
+		return err
+	})
+	if exitCode(err) != exitIO {
+		t.Errorf("write() to a failing writer: exit status %d, expected %d (%v)", exitCode(err), exitIO, err)
+	}
+}
+
+func TestHelp(t *testing.T) {
+	_, stdout, _ := runArgs(t, "", "help")
+	for _, cmd := range commands {
+		if !strings.Contains(stdout, "  "+cmd.name) {
+			t.Errorf("help does not list %s", cmd.name)
+		}
+	}
+	_, stdout, _ = runArgs(t, "", "fit", "--help")
+	for _, want := range []string{"-input", "-format", "-out-dir", "-precision", "-missing", "-sets", "Exit status:"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("fit --help does not mention %s:\n%s", want, stdout)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package anscombe
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// CSVOptions controls how ReadCSV maps the columns of a delimited file onto
+// datasets.
+type CSVOptions struct {
+	Comma       rune   // field delimiter, ',' if zero
+	XColumn     string // header of the x column, "x" if empty
+	YColumn     string // header of the y column, "y" if empty
+	GroupColumn string // header of the column naming each set; optional
+	Name        string // name of the single set read when GroupColumn is empty
+}
+
+// ReadCSV reads delimited text with a header row from r. Column names are
+// matched case-insensitively, and blank or NaN values are read as missing,
+// NaN, for Options.Missing to handle. If opts.GroupColumn is set, every
+// distinct value in that column becomes its own Dataset, in order of first
+// appearance; otherwise all rows form a single Dataset called opts.Name.
+func ReadCSV(r io.Reader, opts CSVOptions) ([]Dataset, error) {
+	var datasets []Dataset
+	index := map[string]int{}
+	err := scanCSV(r, opts, func(name string, x, y float64, _ int) error {
+		i, ok := index[name]
+		if !ok {
+			i = len(datasets)
+			index[name] = i
+			datasets = append(datasets, Dataset{Name: name})
+		}
+		datasets[i].X = append(datasets[i].X, x)
+		datasets[i].Y = append(datasets[i].Y, y)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(datasets) == 0 {
+		return nil, ErrEmptyInput
+	}
+	return datasets, nil
+}
+
+// scanCSV reads delimited text as ReadCSV does and calls row with the set
+// name, x, y and line number of every record, stopping at the first error.
+func scanCSV(r io.Reader, opts CSVOptions, row func(name string, x, y float64, line int) error) error {
+	if opts.Comma == 0 {
+		opts.Comma = ','
+	}
+	if opts.XColumn == "" {
+		opts.XColumn = "x"
+	}
+	if opts.YColumn == "" {
+		opts.YColumn = "y"
+	}
+	cr := csv.NewReader(r)
+	cr.Comma = opts.Comma
+	cr.Comment = '#'
+	cr.TrimLeadingSpace = true
+	cr.ReuseRecord = true
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return ErrEmptyInput
+	}
+	if err != nil {
+		return err
+	}
+	xCol, err := columnIndex(header, opts.XColumn)
+	if err != nil {
+		return err
+	}
+	yCol, err := columnIndex(header, opts.YColumn)
+	if err != nil {
+		return err
+	}
+	groupCol := -1
+	if opts.GroupColumn != "" {
+		if groupCol, err = columnIndex(header, opts.GroupColumn); err != nil {
+			return err
+		}
+	}
+
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		line, _ := cr.FieldPos(0)
+		x, err := parseField(record[xCol], line, opts.XColumn)
+		if err != nil {
+			return err
+		}
+		y, err := parseField(record[yCol], line, opts.YColumn)
+		if err != nil {
+			return err
+		}
+		name := opts.Name
+		if groupCol >= 0 {
+			name = strings.TrimSpace(record[groupCol])
+		}
+		if err := row(name, x, y, line); err != nil {
+			return err
+		}
+	}
+}
+
+func columnIndex(header []string, name string) (int, error) {
+	for i, h := range header {
+		if strings.EqualFold(strings.TrimSpace(h), name) {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("%w: %q", ErrColumn, name)
+}
+
+// parseField parses a number, reading a blank field as a missing value, NaN.
+func parseField(field string, line int, column string) (float64, error) {
+	field = strings.TrimSpace(field)
+	if field == "" {
+		return math.NaN(), nil
+	}
+	v, err := strconv.ParseFloat(field, 64)
+	if err != nil {
+		return 0, fmt.Errorf("line %d, column %q: %w", line, column, err)
+	}
+	return v, nil
+}
@@ -0,0 +1,61 @@
+package anscombe
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestReadCSVGroups(t *testing.T) {
+	datasets, err := LoadFile("testdata/anscombe.csv", CSVOptions{GroupColumn: "dataset"})
+	if err != nil {
+		t.Fatalf("LoadFile() returned an error: %v", err)
+	}
+	quartet := Quartet()
+	if len(datasets) != len(quartet) {
+		t.Fatalf("Expected %d sets, got %d", len(quartet), len(datasets))
+	}
+	for i, name := range []string{"I", "II", "III", "IV"} {
+		if datasets[i].Name != name {
+			t.Errorf("Set %d: expected name %q, got %q", i+1, name, datasets[i].Name)
+		}
+		for j := range quartet[i].X {
+			if datasets[i].X[j] != quartet[i].X[j] || datasets[i].Y[j] != quartet[i].Y[j] {
+				t.Errorf("Set %d, row %d: got (%g, %g), expected (%g, %g)", i+1, j,
+					datasets[i].X[j], datasets[i].Y[j], quartet[i].X[j], quartet[i].Y[j])
+			}
+		}
+	}
+}
+
+func TestReadTSVColumns(t *testing.T) {
+	datasets, err := LoadFile("testdata/people.tsv", CSVOptions{XColumn: "Height", YColumn: "weight"})
+	if err != nil {
+		t.Fatalf("LoadFile() returned an error: %v", err)
+	}
+	if len(datasets) != 1 || datasets[0].Name != "people" {
+		t.Fatalf("Expected a single set called people, got %+v", datasets)
+	}
+	if datasets[0].X[1] != 181.5 || datasets[0].Y[0] != 60.5 {
+		t.Errorf("Columns were not mapped correctly: %+v", datasets[0])
+	}
+}
+
+func TestReadCSVErrors(t *testing.T) {
+	// Test case 1: Missing column
+	_, err := ReadCSV(strings.NewReader("a,y\n1,2\n"), CSVOptions{})
+	if !errors.Is(err, ErrColumn) {
+		t.Errorf("ReadCSV() with a missing column: expected %v, got %v", ErrColumn, err)
+	}
+
+	// Test case 2: Unparsable value
+	_, err = ReadCSV(strings.NewReader("x,y\n1,2\n3,four\n"), CSVOptions{})
+	if err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Errorf("ReadCSV() with a bad value did not report its line: %v", err)
+	}
+
+	// Test case 3: Header only
+	if _, err := ReadCSV(strings.NewReader("x,y\n"), CSVOptions{}); err != ErrEmptyInput {
+		t.Errorf("ReadCSV() with no rows: expected %v, got %v", ErrEmptyInput, err)
+	}
+}
@@ -0,0 +1,70 @@
+// Package anscombe implements the analysis shared by the Anscombe's quartet
+// commands in this repository: data-quality checks, least squares fitting and
+// summary statistics.
+package anscombe
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/montanaflynn/stats"
+)
+
+// Dataset is a named set of paired observations.
+type Dataset struct {
+	Name string    `json:"name"`
+	X    []float64 `json:"x"`
+	Y    []float64 `json:"y"`
+}
+
+// Len returns the number of observations in the dataset.
+func (d Dataset) Len() int {
+	return len(d.X)
+}
+
+// Slug returns the dataset name in a form usable in file names: lower case,
+// with every run of characters other than letters and digits replaced by a
+// single underscore. "Set 1" becomes "set_1".
+func (d Dataset) Slug() string {
+	var b strings.Builder
+	sep := false
+	for _, r := range strings.ToLower(d.Name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if sep && b.Len() > 0 {
+				b.WriteByte('_')
+			}
+			b.WriteRune(r)
+			sep = false
+		} else {
+			sep = true
+		}
+	}
+	if b.Len() == 0 {
+		return "dataset"
+	}
+	return b.String()
+}
+
+// Quartet returns the four sets of Anscombe's quartet, in order.
+func Quartet() []Dataset {
+	x := []float64{10, 8, 13, 9, 11, 14, 6, 4, 12, 7, 5}
+	return []Dataset{
+		{Name: "Set 1", X: x, Y: []float64{8.04, 6.95, 7.58, 8.81, 8.33, 9.96, 7.24, 4.26, 10.84, 4.82, 5.68}},
+		{Name: "Set 2", X: x, Y: []float64{9.14, 8.14, 8.74, 8.77, 9.26, 8.1, 6.13, 3.1, 9.13, 7.26, 4.74}},
+		{Name: "Set 3", X: x, Y: []float64{7.46, 6.77, 12.74, 7.11, 7.81, 8.84, 6.08, 5.39, 8.15, 6.42, 5.73}},
+		{Name: "Set 4", X: []float64{8, 8, 8, 8, 8, 8, 8, 19, 8, 8, 8}, Y: []float64{6.58, 5.76, 7.71, 8.84, 8.47, 7.04, 5.25, 12.5, 5.56, 7.91, 6.89}},
+	}
+}
+
+// MakeSeries pairs x and y into a stats.Series. It returns nil if the slices
+// have different lengths.
+func MakeSeries(x, y []float64) stats.Series {
+	if len(x) != len(y) {
+		return nil
+	}
+	series := make(stats.Series, len(x))
+	for i := range x {
+		series[i] = stats.Coordinate{X: x[i], Y: y[i]}
+	}
+	return series
+}
@@ -0,0 +1,147 @@
+package anscombe
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// DDOF is the delta degrees of freedom of a variance: the sum of squared
+// deviations about the mean is divided by n - DDOF.
+type DDOF int
+
+const (
+	// Population divides by n, giving the variance of the data themselves.
+	Population DDOF = 0
+	// Sample divides by n-1, giving the unbiased estimate of the variance of
+	// the population the data were drawn from. Anscombe's published summary
+	// statistics use this convention.
+	Sample DDOF = 1
+)
+
+// String describes the convention, e.g. "sample (n-1)".
+func (d DDOF) String() string {
+	switch d {
+	case Population:
+		return "population (n)"
+	case Sample:
+		return "sample (n-1)"
+	}
+	return fmt.Sprintf("n-%d", int(d))
+}
+
+// Mean returns the arithmetic mean of x, summed with compensation.
+func Mean(x []float64) (float64, error) {
+	if len(x) == 0 {
+		return 0, ErrEmptyInput
+	}
+	var sum KahanSum
+	for _, v := range x {
+		sum.Add(v)
+	}
+	return sum.Sum() / float64(len(x)), nil
+}
+
+// Variance returns the variance of x, dividing the compensated sum of squared
+// deviations from the mean by n - ddof. It fails unless x has more than ddof
+// values.
+func Variance(x []float64, ddof DDOF) (float64, error) {
+	if len(x) == 0 {
+		return 0, ErrEmptyInput
+	}
+	if ddof < 0 || len(x) <= int(ddof) {
+		return 0, ErrTooFew
+	}
+	m, _ := Mean(x)
+	var sum KahanSum
+	for _, v := range x {
+		sum.Add((v - m) * (v - m))
+	}
+	return sum.Sum() / float64(len(x)-int(ddof)), nil
+}
+
+// StdDev returns the square root of Variance(x, ddof).
+func StdDev(x []float64, ddof DDOF) (float64, error) {
+	v, err := Variance(x, ddof)
+	if err != nil {
+		return 0, err
+	}
+	return math.Sqrt(v), nil
+}
+
+// Description summarizes the distribution of one variable.
+type Description struct {
+	Count          int     `json:"count"`
+	Mean           float64 `json:"mean"`
+	Median         float64 `json:"median"`
+	Q1             float64 `json:"q1"`  // first quartile
+	Q3             float64 `json:"q3"`  // third quartile
+	IQR            float64 `json:"iqr"` // interquartile range, Q3 - Q1
+	Min            float64 `json:"min"`
+	Max            float64 `json:"max"`
+	DDOF           DDOF    `json:"ddof"` // variance convention of Variance, StdDev and CV
+	Variance       float64 `json:"variance"`
+	StdDev         float64 `json:"std_dev"`
+	Skewness       Float   `json:"skewness"`        // moment coefficient m3/m2^1.5, null for constant data
+	ExcessKurtosis Float   `json:"excess_kurtosis"` // m4/m2^2 - 3, null for constant data
+	MAD            float64 `json:"mad"`             // median absolute deviation from the median, unscaled
+	CV             Float   `json:"cv"`              // coefficient of variation, StdDev/Mean, null if the mean is zero
+}
+
+// Describe returns the descriptive statistics of x, with variances divided by
+// n - ddof. Quartiles interpolate linearly between order statistics, as R's
+// default quantile type 7 does. Skewness and kurtosis use the population
+// central moments m2, m3 and m4 without small-sample correction.
+func Describe(x []float64, ddof DDOF) (Description, error) {
+	variance, err := Variance(x, ddof)
+	if err != nil {
+		return Description{}, err
+	}
+	sorted := append([]float64(nil), x...)
+	sort.Float64s(sorted)
+	d := Description{
+		Count:    len(x),
+		Median:   quantile(sorted, 0.5),
+		Q1:       quantile(sorted, 0.25),
+		Q3:       quantile(sorted, 0.75),
+		Min:      sorted[0],
+		Max:      sorted[len(sorted)-1],
+		DDOF:     ddof,
+		Variance: variance,
+		StdDev:   math.Sqrt(variance),
+		MAD:      medianAbsoluteDeviation(x),
+	}
+	d.Mean, _ = Mean(x)
+	d.IQR = d.Q3 - d.Q1
+
+	var m2, m3, m4 float64
+	for _, v := range x {
+		dev := v - d.Mean
+		m2 += dev * dev
+		m3 += dev * dev * dev
+		m4 += dev * dev * dev * dev
+	}
+	n := float64(len(x))
+	m2, m3, m4 = m2/n, m3/n, m4/n
+	d.Skewness, d.ExcessKurtosis, d.CV = Float(math.NaN()), Float(math.NaN()), Float(math.NaN())
+	if m2 > 0 {
+		d.Skewness = Float(m3 / math.Pow(m2, 1.5))
+		d.ExcessKurtosis = Float(m4/(m2*m2) - 3)
+	}
+	if d.Mean != 0 {
+		d.CV = Float(d.StdDev / d.Mean)
+	}
+	return d, nil
+}
+
+// quantile returns the p-quantile of sorted by linear interpolation between
+// the order statistics at (n-1)p.
+func quantile(sorted []float64, p float64) float64 {
+	h := float64(len(sorted)-1) * p
+	lo := math.Floor(h)
+	i := int(lo)
+	if i+1 >= len(sorted) {
+		return sorted[i]
+	}
+	return sorted[i] + (h-lo)*(sorted[i+1]-sorted[i])
+}
@@ -0,0 +1,144 @@
+package anscombe
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMean(t *testing.T) {
+	// Test case 1: Valid data
+	x := []float64{1, 2, 3, 4, 5}
+	meanValue, err := Mean(x)
+	if err != nil {
+		t.Errorf("Mean() returned an error for valid data: %v", err)
+	}
+	if math.Abs(meanValue-3.0) > 1e-6 {
+		t.Errorf("Mean() returned an incorrect mean value: expected 3.0, got %f", meanValue)
+	}
+
+	// Test case 2: Empty data
+	meanValue, err = Mean([]float64{})
+	if err == nil {
+		t.Error("Mean() did not return an error for empty data")
+	}
+	if meanValue != 0 {
+		t.Errorf("Mean() did not return 0.0 for empty data: got %f", meanValue)
+	}
+}
+
+func TestVariance(t *testing.T) {
+	// Test case 1: Valid data
+	x := []float64{1, 2, 3, 4, 5}
+	varianceValue, err := Variance(x, Sample)
+	if err != nil {
+		t.Errorf("Variance() returned an error for valid data: %v", err)
+	}
+	if math.Abs(varianceValue-2.5) > 1e-6 {
+		t.Errorf("Variance() returned an incorrect variance value: expected 2.5, got %f", varianceValue)
+	}
+
+	// Test case 2: Empty data
+	varianceValue, err = Variance([]float64{}, Sample)
+	if err == nil {
+		t.Error("Variance() did not return an error for empty data")
+	}
+	if varianceValue != 0 {
+		t.Errorf("Variance() did not return 0.0 for empty data: got %f", varianceValue)
+	}
+
+	// Test case 3: Population variance
+	varianceValue, err = Variance(x, Population)
+	if err != nil || math.Abs(varianceValue-2.0) > 1e-6 {
+		t.Errorf("Variance() returned an incorrect population variance: expected 2.0, got %f (%v)", varianceValue, err)
+	}
+
+	// Test case 4: Too few values for the degrees of freedom
+	if _, err := Variance([]float64{4}, Sample); err != ErrTooFew {
+		t.Errorf("Variance() of one value with ddof 1: expected %v, got %v", ErrTooFew, err)
+	}
+}
+
+func TestDDOFString(t *testing.T) {
+	if Sample.String() != "sample (n-1)" || Population.String() != "population (n)" || DDOF(2).String() != "n-2" {
+		t.Errorf("Unexpected DDOF descriptions: %v, %v, %v", Sample, Population, DDOF(2))
+	}
+}
+
+func TestStdDev(t *testing.T) {
+	x := []float64{1, 2, 3, 4, 5}
+	stdDev, err := StdDev(x, Sample)
+	if err != nil {
+		t.Errorf("StdDev() returned an error for valid data: %v", err)
+	}
+	if math.Abs(stdDev-math.Sqrt(2.5)) > 1e-6 {
+		t.Errorf("StdDev() returned an incorrect standard deviation value: expected %f, got %f", math.Sqrt(2.5), stdDev)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	quartet := Quartet()
+	tests := []struct {
+		name string
+		x    []float64
+		want Description
+	}{
+		// Test case 1: x of sets 1-3, the integers 4 to 14
+		{"x1", quartet[0].X, Description{Count: 11, Mean: 9, Median: 9, Q1: 6.5, Q3: 11.5, IQR: 5, Min: 4, Max: 14,
+			Variance: 11, StdDev: math.Sqrt(11), Skewness: 0, ExcessKurtosis: -1.22, MAD: 3, CV: Float(math.Sqrt(11) / 9)}},
+		// Test case 2: y of set 1; quartiles as given by R's summary()
+		{"y1", quartet[0].Y, Description{Count: 11, Mean: 7.500909, Median: 7.58, Q1: 6.315, Q3: 8.57, IQR: 2.255, Min: 4.26, Max: 10.84,
+			Variance: 4.127269, StdDev: 2.031568, Skewness: -0.055808, ExcessKurtosis: -0.820939, MAD: 1.23, CV: 0.27084}},
+		// Test case 3: x of set 4, ten 8s and a 19
+		{"x4", quartet[3].X, Description{Count: 11, Mean: 9, Median: 8, Q1: 8, Q3: 8, IQR: 0, Min: 8, Max: 19,
+			Variance: 11, StdDev: math.Sqrt(11), Skewness: 2.846050, ExcessKurtosis: 6.1, MAD: 0, CV: Float(math.Sqrt(11) / 9)}},
+	}
+	for _, tt := range tests {
+		got, err := Describe(tt.x, Sample)
+		if err != nil {
+			t.Fatalf("Describe(%s) returned an error: %v", tt.name, err)
+		}
+		if got.Count != tt.want.Count || got.DDOF != Sample {
+			t.Errorf("Describe(%s) = %+v", tt.name, got)
+		}
+		for _, c := range []struct {
+			field     string
+			got, want float64
+		}{
+			{"Mean", got.Mean, tt.want.Mean},
+			{"Median", got.Median, tt.want.Median},
+			{"Q1", got.Q1, tt.want.Q1},
+			{"Q3", got.Q3, tt.want.Q3},
+			{"IQR", got.IQR, tt.want.IQR},
+			{"Min", got.Min, tt.want.Min},
+			{"Max", got.Max, tt.want.Max},
+			{"Variance", got.Variance, tt.want.Variance},
+			{"StdDev", got.StdDev, tt.want.StdDev},
+			{"Skewness", float64(got.Skewness), float64(tt.want.Skewness)},
+			{"ExcessKurtosis", float64(got.ExcessKurtosis), float64(tt.want.ExcessKurtosis)},
+			{"MAD", got.MAD, tt.want.MAD},
+			{"CV", float64(got.CV), float64(tt.want.CV)},
+		} {
+			if math.Abs(c.got-c.want) > 1e-4 {
+				t.Errorf("Describe(%s).%s = %g, expected %g", tt.name, c.field, c.got, c.want)
+			}
+		}
+	}
+
+	// Test case 4: constant data has no shape and zero-mean data no CV
+	got, err := Describe([]float64{-1, -1, 1, 1}, Population)
+	if err != nil || got.Variance != 1 || !math.IsNaN(float64(got.CV)) {
+		t.Errorf("Describe() of zero-mean data = %+v, %v", got, err)
+	}
+	got, _ = Describe([]float64{2, 2, 2}, Sample)
+	if !math.IsNaN(float64(got.Skewness)) || !math.IsNaN(float64(got.ExcessKurtosis)) {
+		t.Errorf("Describe() of constant data = %+v", got)
+	}
+
+	// Test case 5: empty input and too few values for the convention
+	if _, err := Describe(nil, Sample); err != ErrEmptyInput {
+		t.Errorf("Describe() of empty input: expected %v, got %v", ErrEmptyInput, err)
+	}
+	if _, err := Describe([]float64{1}, Sample); err != ErrTooFew {
+		t.Errorf("Describe() of one value: expected %v, got %v", ErrTooFew, err)
+	}
+}
@@ -0,0 +1,110 @@
+package anscombe
+
+import (
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrEmptyInput = statsError{"Input must not be empty."}
+	ErrNaN        = statsError{"Not a number."}
+	ErrNegative   = statsError{"Must not contain negative values."}
+	ErrZero       = statsError{"Must not contain zero values."}
+	ErrBounds     = statsError{"Input is outside of range."}
+	ErrSize       = statsError{"Must be the same length."}
+	ErrTooFew     = statsError{"Too few points."}
+	ErrInfValue   = statsError{"Value is infinite."}
+	ErrYCoord     = statsError{"Y Value must be greater than zero."}
+	ErrColumn     = statsError{"Column not found."}
+	ErrDuplicate  = statsError{"Dataset name must be unique."}
+	ErrConverge   = statsError{"Estimate did not converge."}
+	ErrConstant   = statsError{"Values must not all be equal."}
+	ErrExtreme    = statsError{"Value is extreme."}
+	ErrCollinear  = statsError{"X is nearly collinear with the intercept."}
+
+	ErrDuplicatePoint = statsError{"Point is repeated."}
+)
+
+type statsError struct {
+	msg string
+}
+
+func (e statsError) Error() string {
+	return e.msg
+}
+
+// SetError is a failure to analyze or plot one dataset. It wraps the error
+// that caused it, so errors.Is and errors.As see through to the sentinels
+// above.
+type SetError struct {
+	Set string // name of the dataset
+	Err error
+}
+
+func (e *SetError) Error() string {
+	return e.Set + ": " + e.Err.Error()
+}
+
+func (e *SetError) Unwrap() error {
+	return e.Err
+}
+
+// ValueError is a problem with the values of one variable of a dataset,
+// usually with the value at Index. It wraps one of the sentinels above, which
+// errors.Is matches. The message leaves out the set, since it is reported
+// with the set by SetError and in Results.
+type ValueError struct {
+	Set      string  // name of the dataset, empty if not known
+	Variable string  // "x" or "y", or empty if the problem is with both
+	Index    int     // position of the offending value, or -1 if there is none
+	Value    float64 // the offending value, if Index is not -1 and Variable is set
+	Err      error
+}
+
+func (e *ValueError) Error() string {
+	switch {
+	case e.Index >= 0 && e.Variable == "":
+		// A problem with the observation as a whole, such as a repeated point
+		return fmt.Sprintf("(x, y)[%d]: %v", e.Index, e.Err)
+	case e.Index >= 0:
+		return fmt.Sprintf("%s[%d] = %v: %v", e.Variable, e.Index, e.Value, e.Err)
+	case e.Variable != "":
+		return e.Variable + ": " + e.Err.Error()
+	}
+	return e.Err.Error()
+}
+
+func (e *ValueError) Unwrap() error {
+	return e.Err
+}
+
+// maxErrorsShown bounds the problems listed in the message of Errors.
+const maxErrorsShown = 5
+
+// Errors is every problem found in one pass over a dataset, in the order
+// found. errors.Is and errors.As match any of them.
+type Errors []error
+
+func (e Errors) Error() string {
+	msgs := make([]string, 0, maxErrorsShown+1)
+	for i, err := range e {
+		if i == maxErrorsShown {
+			msgs = append(msgs, fmt.Sprintf("and %d more", len(e)-i))
+			break
+		}
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (e Errors) Unwrap() []error {
+	return e
+}
+
+// err returns e, or nil if it holds no errors.
+func (e Errors) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
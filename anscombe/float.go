@@ -0,0 +1,35 @@
+package anscombe
+
+import (
+	"encoding/json"
+	"math"
+)
+
+// Float is a float64 that encodes NaN and infinities as JSON null instead of
+// failing, and decodes null back to NaN. It is used for statistics that are
+// undefined for some data, such as the studentized residual of a point with
+// leverage one, or the R-squared of a constant y and the t and F statistics
+// of a perfect fit.
+type Float float64
+
+// MarshalJSON implements json.Marshaler.
+func (f Float) MarshalJSON() ([]byte, error) {
+	if math.IsNaN(float64(f)) || math.IsInf(float64(f), 0) {
+		return []byte("null"), nil
+	}
+	return json.Marshal(float64(f))
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (f *Float) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		*f = Float(math.NaN())
+		return nil
+	}
+	var v float64
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*f = Float(v)
+	return nil
+}
@@ -0,0 +1,90 @@
+package anscombe
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// Coefficient holds the inference statistics for one estimated coefficient.
+type Coefficient struct {
+	Estimate float64 `json:"estimate"`
+	StdErr   float64 `json:"std_err"`
+	T        Float   `json:"t"`     // Estimate / StdErr, not finite for a perfect fit
+	P        Float   `json:"p"`     // two-sided p-value of T under the Student t distribution
+	Lower    float64 `json:"lower"` // lower bound of the confidence interval
+	Upper    float64 `json:"upper"` // upper bound of the confidence interval
+}
+
+// Inference holds the normal-theory inference statistics of a Fit.
+type Inference struct {
+	Level          float64     `json:"level"` // confidence level of the intervals, e.g. 0.95
+	Intercept      Coefficient `json:"intercept"`
+	Slope          Coefficient `json:"slope"`
+	ResidualStdErr float64     `json:"residual_std_err"` // sqrt(SSE / DF)
+	F              Float       `json:"f"`                // F statistic of the regression on 1 and DF degrees of freedom
+	FP             Float       `json:"f_p"`              // p-value of F
+}
+
+// Inference computes standard errors, t statistics, p-values and confidence
+// intervals at the given level for the coefficients of f, along with the
+// residual standard error and the F statistic of the regression.
+func (f Fit) Inference(level float64) (Inference, error) {
+	if f.DF < 1 {
+		return Inference{}, ErrTooFew
+	}
+	if !(level > 0 && level < 1) {
+		return Inference{}, ErrBounds
+	}
+	n := float64(f.N())
+	df := float64(f.DF)
+	s := math.Sqrt(f.SSE / df)
+	t := distuv.StudentsT{Mu: 0, Sigma: 1, Nu: df}
+	q := t.Quantile(1 - (1-level)/2)
+
+	coefficient := func(estimate, stdErr float64) Coefficient {
+		c := Coefficient{Estimate: estimate, StdErr: stdErr, T: Float(estimate / stdErr)}
+		c.P = Float(2 * t.Survival(math.Abs(float64(c.T))))
+		c.Lower = estimate - q*stdErr
+		c.Upper = estimate + q*stdErr
+		return c
+	}
+	inf := Inference{
+		Level:          level,
+		Intercept:      coefficient(f.Intercept, s*math.Sqrt(1/n+f.MeanX*f.MeanX/f.Sxx)),
+		Slope:          coefficient(f.Slope, s/math.Sqrt(f.Sxx)),
+		ResidualStdErr: s,
+		F:              Float((f.SST - f.SSE) / (f.SSE / df)),
+	}
+	inf.FP = Float(distuv.F{D1: 1, D2: df}.Survival(float64(inf.F)))
+	return inf, nil
+}
+
+// MeanInterval returns the confidence interval at the given level for the
+// mean response at x.
+func (f Fit) MeanInterval(x, level float64) (lower, upper float64, err error) {
+	return f.interval(x, level, 0)
+}
+
+// PredictionInterval returns the prediction interval at the given level for a
+// new observation at x. It is wider than MeanInterval by the residual
+// variance of a single observation.
+func (f Fit) PredictionInterval(x, level float64) (lower, upper float64, err error) {
+	return f.interval(x, level, 1)
+}
+
+func (f Fit) interval(x, level, extra float64) (lower, upper float64, err error) {
+	if f.DF < 1 {
+		return 0, 0, ErrTooFew
+	}
+	if !(level > 0 && level < 1) {
+		return 0, 0, ErrBounds
+	}
+	df := float64(f.DF)
+	s := math.Sqrt(f.SSE / df)
+	q := distuv.StudentsT{Mu: 0, Sigma: 1, Nu: df}.Quantile(1 - (1-level)/2)
+	dx := x - f.MeanX
+	half := q * s * math.Sqrt(extra+1/float64(f.N())+dx*dx/f.Sxx)
+	y := f.Predict(x)
+	return y - half, y + half, nil
+}
@@ -0,0 +1,106 @@
+package anscombe
+
+import (
+	"math"
+	"testing"
+)
+
+func TestInferenceQuartet(t *testing.T) {
+	// Published values for every set of the quartet, which agree to about
+	// three significant figures.
+	for _, d := range Quartet() {
+		fit, err := LinearRegression(d.X, d.Y)
+		if err != nil {
+			t.Fatalf("LinearRegression(%s) returned an error: %v", d.Name, err)
+		}
+		inf, err := fit.Inference(0.95)
+		if err != nil {
+			t.Fatalf("Inference(%s) returned an error: %v", d.Name, err)
+		}
+		checks := []struct {
+			name      string
+			got, want float64
+			tol       float64
+		}{
+			{"slope std. error", inf.Slope.StdErr, 0.118, 0.001},
+			{"intercept std. error", inf.Intercept.StdErr, 1.125, 0.002},
+			{"slope t value", float64(inf.Slope.T), 4.24, 0.01},
+			{"intercept t value", float64(inf.Intercept.T), 2.67, 0.01},
+			{"slope p-value", float64(inf.Slope.P), 0.00217, 0.00002},
+			{"intercept p-value", float64(inf.Intercept.P), 0.0257, 0.0002},
+			{"slope lower bound", inf.Slope.Lower, 0.233, 0.001},
+			{"slope upper bound", inf.Slope.Upper, 0.767, 0.001},
+			{"intercept lower bound", inf.Intercept.Lower, 0.456, 0.005},
+			{"intercept upper bound", inf.Intercept.Upper, 5.544, 0.005},
+			{"residual std. error", inf.ResidualStdErr, 1.237, 0.002},
+			{"F statistic", float64(inf.F), 17.99, 0.05},
+			{"F p-value", float64(inf.FP), 0.00217, 0.00002},
+		}
+		for _, c := range checks {
+			if math.Abs(c.got-c.want) > c.tol {
+				t.Errorf("%s: expected %s %g, got %g", d.Name, c.name, c.want, c.got)
+			}
+		}
+		// The F statistic of a simple regression is the square of the slope t.
+		if math.Abs(float64(inf.F-inf.Slope.T*inf.Slope.T)) > 1e-9 {
+			t.Errorf("%s: F statistic %g is not the square of slope t %g", d.Name, inf.F, inf.Slope.T)
+		}
+	}
+}
+
+func TestInferenceErrors(t *testing.T) {
+	fit, err := LinearRegression([]float64{1, 2}, []float64{1, 2})
+	if err != nil {
+		t.Fatalf("LinearRegression() returned an error: %v", err)
+	}
+	if _, err := fit.Inference(0.95); err != ErrTooFew {
+		t.Errorf("Inference() with no residual degrees of freedom: expected %v, got %v", ErrTooFew, err)
+	}
+	fit, _ = LinearRegression([]float64{1, 2, 3}, []float64{1, 3, 2})
+	if _, err := fit.Inference(1.5); err != ErrBounds {
+		t.Errorf("Inference() with level 1.5: expected %v, got %v", ErrBounds, err)
+	}
+	if _, err := fit.Inference(math.NaN()); err != ErrBounds {
+		t.Errorf("Inference() with level NaN: expected %v, got %v", ErrBounds, err)
+	}
+	if _, _, err := fit.MeanInterval(2, math.NaN()); err != ErrBounds {
+		t.Errorf("MeanInterval() with level NaN: expected %v, got %v", ErrBounds, err)
+	}
+}
+
+func TestIntervals(t *testing.T) {
+	d := Quartet()[0]
+	fit, err := LinearRegression(d.X, d.Y)
+	if err != nil {
+		t.Fatalf("LinearRegression() returned an error: %v", err)
+	}
+	inf, _ := fit.Inference(0.95)
+
+	// At x = 0 the mean interval is the intercept's confidence interval.
+	lower, upper, err := fit.MeanInterval(0, 0.95)
+	if err != nil {
+		t.Fatalf("MeanInterval() returned an error: %v", err)
+	}
+	if math.Abs(lower-inf.Intercept.Lower) > 1e-9 || math.Abs(upper-inf.Intercept.Upper) > 1e-9 {
+		t.Errorf("MeanInterval(0) = [%g, %g], expected the intercept interval [%g, %g]",
+			lower, upper, inf.Intercept.Lower, inf.Intercept.Upper)
+	}
+
+	// At the mean of x the prediction interval is yhat +- t*s*sqrt(1 + 1/n).
+	lower, upper, err = fit.PredictionInterval(9, 0.95)
+	if err != nil {
+		t.Fatalf("PredictionInterval() returned an error: %v", err)
+	}
+	half := 2.262157 * inf.ResidualStdErr * math.Sqrt(1+1.0/11)
+	if math.Abs((upper-lower)/2-half) > 1e-5 || math.Abs((upper+lower)/2-fit.Predict(9)) > 1e-9 {
+		t.Errorf("PredictionInterval(9) = [%g, %g], expected %g +- %g", lower, upper, fit.Predict(9), half)
+	}
+	mLower, mUpper, _ := fit.MeanInterval(9, 0.95)
+	if mLower <= lower || mUpper >= upper {
+		t.Errorf("Mean interval [%g, %g] is not inside the prediction interval [%g, %g]", mLower, mUpper, lower, upper)
+	}
+
+	if _, _, err := fit.MeanInterval(9, 0); err != ErrBounds {
+		t.Errorf("MeanInterval() with level 0: expected %v, got %v", ErrBounds, err)
+	}
+}
@@ -0,0 +1,111 @@
+package anscombe
+
+import "math"
+
+// Influence holds the influence measures of one observation on a Fit.
+// Measures that are undefined for the observation, as every one but the
+// leverage is for a point with leverage one, are NaN.
+type Influence struct {
+	Index       int      `json:"index"` // position of the observation in the dataset
+	X           float64  `json:"x"`
+	Y           float64  `json:"y"`
+	Leverage    Float    `json:"leverage"`    // hat value h
+	Studentized Float    `json:"studentized"` // externally studentized residual
+	CooksD      Float    `json:"cooks_d"`     // Cook's distance
+	DFFITS      Float    `json:"dffits"`
+	DFBETAS     [2]Float `json:"dfbetas"`         // scaled change in intercept and slope when the point is left out
+	Flags       []string `json:"flags,omitempty"` // measures above their threshold, see InfluenceThresholds
+}
+
+// Thresholds are the conventional cut-offs above which an influence measure
+// flags an observation.
+type Thresholds struct {
+	Leverage    float64 `json:"leverage"`    // 2p/n
+	Studentized float64 `json:"studentized"` // 2
+	CooksD      float64 `json:"cooks_d"`     // 4/n
+	DFFITS      float64 `json:"dffits"`      // 2*sqrt(p/n)
+	DFBETAS     float64 `json:"dfbetas"`     // 2/sqrt(n)
+}
+
+// InfluenceThresholds returns the thresholds for a straight-line fit, with
+// p = 2 coefficients, to n observations.
+func InfluenceThresholds(n int) Thresholds {
+	const p = 2
+	nf := float64(n)
+	return Thresholds{
+		Leverage:    2 * p / nf,
+		Studentized: 2,
+		CooksD:      4 / nf,
+		DFFITS:      2 * math.Sqrt(p/nf),
+		DFBETAS:     2 / math.Sqrt(nf),
+	}
+}
+
+// Influence returns the influence measures of every observation on f, which
+// must have been fitted to x. Each observation is flagged for every measure
+// whose magnitude exceeds InfluenceThresholds. Leaving one point out must
+// still leave a residual degree of freedom, so f needs DF >= 2.
+func (f Fit) Influence(x []float64) ([]Influence, error) {
+	if len(x) != f.N() {
+		return nil, ErrSize
+	}
+	if f.DF < 2 {
+		return nil, ErrTooFew
+	}
+	const p = 2
+	n := float64(f.N())
+	limits := InfluenceThresholds(f.N())
+	std := f.StandardizedResiduals()
+	// Standard errors of the coefficients, without the residual scale.
+	seIntercept := math.Sqrt(1/n + f.MeanX*f.MeanX/f.Sxx)
+	seSlope := math.Sqrt(1 / f.Sxx)
+
+	measures := make([]Influence, f.N())
+	for i, e := range f.Residuals {
+		h := f.Leverage[i]
+		in := Influence{Index: i, X: x[i], Y: f.Fitted[i] + e, Leverage: Float(h)}
+		if math.IsNaN(std[i]) {
+			nan := Float(math.NaN())
+			in.Studentized, in.CooksD, in.DFFITS, in.DFBETAS = nan, nan, nan, [2]Float{nan, nan}
+		} else {
+			// Residual variance with observation i left out.
+			s2 := (f.SSE - e*e/(1-h)) / float64(f.DF-1)
+			si := math.Sqrt(s2)
+			t := e / (si * math.Sqrt(1-h))
+			dx := x[i] - f.MeanX
+			in.Studentized = Float(t)
+			in.CooksD = Float(std[i] * std[i] * h / (p * (1 - h)))
+			in.DFFITS = Float(t * math.Sqrt(h/(1-h)))
+			in.DFBETAS = [2]Float{
+				Float((1/n - f.MeanX*dx/f.Sxx) * e / (1 - h) / (si * seIntercept)),
+				Float(dx / f.Sxx * e / (1 - h) / (si * seSlope)),
+			}
+		}
+		in.Flags = flags(in, limits)
+		measures[i] = in
+	}
+	return measures, nil
+}
+
+func flags(in Influence, limits Thresholds) []string {
+	var flags []string
+	exceeds := func(v Float, limit float64) bool {
+		return math.Abs(float64(v)) > limit
+	}
+	if exceeds(in.Leverage, limits.Leverage) {
+		flags = append(flags, "leverage")
+	}
+	if exceeds(in.Studentized, limits.Studentized) {
+		flags = append(flags, "studentized")
+	}
+	if exceeds(in.CooksD, limits.CooksD) {
+		flags = append(flags, "cooks_d")
+	}
+	if exceeds(in.DFFITS, limits.DFFITS) {
+		flags = append(flags, "dffits")
+	}
+	if exceeds(in.DFBETAS[0], limits.DFBETAS) || exceeds(in.DFBETAS[1], limits.DFBETAS) {
+		flags = append(flags, "dfbetas")
+	}
+	return flags
+}
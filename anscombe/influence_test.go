@@ -0,0 +1,128 @@
+package anscombe
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// TestInfluenceLeaveOneOut checks the closed forms against refitting the line
+// with each observation left out.
+func TestInfluenceLeaveOneOut(t *testing.T) {
+	d := Quartet()[2]
+	fit, err := LinearRegression(d.X, d.Y)
+	if err != nil {
+		t.Fatalf("LinearRegression() returned an error: %v", err)
+	}
+	measures, err := fit.Influence(d.X)
+	if err != nil {
+		t.Fatalf("Influence() returned an error: %v", err)
+	}
+	n := float64(d.Len())
+	for i := range d.X {
+		x := append(append([]float64{}, d.X[:i]...), d.X[i+1:]...)
+		y := append(append([]float64{}, d.Y[:i]...), d.Y[i+1:]...)
+		loo, err := LinearRegression(x, y)
+		if err != nil {
+			t.Fatalf("LinearRegression() without point %d returned an error: %v", i, err)
+		}
+		si := math.Sqrt(loo.SSE / float64(loo.DF))
+		h := fit.Leverage[i]
+		wantDFFITS := (fit.Fitted[i] - loo.Predict(d.X[i])) / (si * math.Sqrt(h))
+		wantIntercept := (fit.Intercept - loo.Intercept) / (si * math.Sqrt(1/n+fit.MeanX*fit.MeanX/fit.Sxx))
+		wantSlope := (fit.Slope - loo.Slope) / (si * math.Sqrt(1/fit.Sxx))
+		wantT := (d.Y[i] - loo.Predict(d.X[i])) / (si * math.Sqrt(1+(d.X[i]-loo.MeanX)*(d.X[i]-loo.MeanX)/loo.Sxx+1/(n-1)))
+		var sumSq float64
+		for j := range d.X {
+			diff := fit.Predict(d.X[j]) - loo.Predict(d.X[j])
+			sumSq += diff * diff
+		}
+		wantCook := sumSq / (2 * fit.SSE / float64(fit.DF))
+
+		m := measures[i]
+		for _, c := range []struct {
+			name      string
+			got, want float64
+		}{
+			{"DFFITS", float64(m.DFFITS), wantDFFITS},
+			{"DFBETAS intercept", float64(m.DFBETAS[0]), wantIntercept},
+			{"DFBETAS slope", float64(m.DFBETAS[1]), wantSlope},
+			{"studentized residual", float64(m.Studentized), wantT},
+			{"Cook's distance", float64(m.CooksD), wantCook},
+		} {
+			if math.Abs(c.got-c.want) > 1e-9*math.Max(1, math.Abs(c.want)) {
+				t.Errorf("Point %d: %s %g, expected %g", i, c.name, c.got, c.want)
+			}
+		}
+	}
+
+	// The outlier is flagged by everything but leverage; the point at x = 14
+	// only moves the intercept and slope past 2/sqrt(n).
+	for i, m := range measures {
+		want := []string(nil)
+		switch i {
+		case 2:
+			want = []string{"studentized", "cooks_d", "dffits", "dfbetas"}
+		case 5:
+			want = []string{"dfbetas"}
+		}
+		if !reflect.DeepEqual(m.Flags, want) {
+			t.Errorf("Point %d: flags %v, expected %v", i, m.Flags, want)
+		}
+	}
+}
+
+func TestInfluenceLeverageOne(t *testing.T) {
+	d := Quartet()[3]
+	s, err := Summarize(d, DefaultOptions())
+	if err != nil {
+		t.Fatalf("Summarize() returned an error: %v", err)
+	}
+	flagged := s.Flagged()
+	if len(flagged) != 1 || flagged[0].Index != 7 || flagged[0].X != 19 || flagged[0].Y != 12.5 {
+		t.Fatalf("Expected only the point at x = 19 to be flagged, got %+v", flagged)
+	}
+	if !math.IsNaN(float64(flagged[0].CooksD)) || flagged[0].Flags[0] != "leverage" {
+		t.Errorf("Expected an undefined Cook's distance and a leverage flag, got %+v", flagged[0])
+	}
+
+	// Undefined measures survive a trip through the results document.
+	var buf bytes.Buffer
+	if err := WriteResults(&buf, Results{Sets: []SetResult{{Summary: s}}}); err != nil {
+		t.Fatalf("WriteResults() returned an error: %v", err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte(`"cooks_d": null`)) {
+		t.Error("Undefined Cook's distance was not written as null")
+	}
+	results, err := ReadResults(&buf)
+	if err != nil {
+		t.Fatalf("ReadResults() returned an error: %v", err)
+	}
+	if got := results.Sets[0].Influence[7]; !math.IsNaN(float64(got.Studentized)) || got.Leverage != 1 {
+		t.Errorf("Decoded influence of x = 19 is %+v", got)
+	}
+}
+
+func TestFloatJSON(t *testing.T) {
+	b, err := json.Marshal([]Float{1.5, Float(math.Inf(1)), Float(math.NaN())})
+	if err != nil || string(b) != "[1.5,null,null]" {
+		t.Errorf("json.Marshal() = %s, %v", b, err)
+	}
+	var f []Float
+	if err := json.Unmarshal([]byte("[2, null]"), &f); err != nil || f[0] != 2 || !math.IsNaN(float64(f[1])) {
+		t.Errorf("json.Unmarshal() = %v, %v", f, err)
+	}
+}
+
+func TestInfluenceErrors(t *testing.T) {
+	fit, _ := LinearRegression([]float64{1, 2, 3}, []float64{1, 3, 2})
+	if _, err := fit.Influence([]float64{1, 2, 3}); err != ErrTooFew {
+		t.Errorf("Influence() with one residual degree of freedom: expected %v, got %v", ErrTooFew, err)
+	}
+	fit, _ = LinearRegression([]float64{1, 2, 3, 4}, []float64{1, 3, 2, 4})
+	if _, err := fit.Influence([]float64{1, 2}); err != ErrSize {
+		t.Errorf("Influence() with the wrong x: expected %v, got %v", ErrSize, err)
+	}
+}
@@ -0,0 +1,129 @@
+package anscombe
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// datasetJSON is the JSON form of a Dataset, in which a missing value, NaN,
+// is null.
+type datasetJSON struct {
+	Name string  `json:"name"`
+	X    []Float `json:"x"`
+	Y    []Float `json:"y"`
+}
+
+// MarshalJSON implements json.Marshaler, writing NaN and infinite values as
+// null.
+func (d Dataset) MarshalJSON() ([]byte, error) {
+	return json.Marshal(datasetJSON{Name: d.Name, X: toFloats(d.X), Y: toFloats(d.Y)})
+}
+
+// UnmarshalJSON implements json.Unmarshaler, reading null as a missing value,
+// NaN.
+func (d *Dataset) UnmarshalJSON(b []byte) error {
+	var dj datasetJSON
+	if err := json.Unmarshal(b, &dj); err != nil {
+		return err
+	}
+	*d = Dataset{Name: dj.Name, X: fromFloats(dj.X), Y: fromFloats(dj.Y)}
+	return nil
+}
+
+func toFloats(x []float64) []Float {
+	if x == nil {
+		return nil
+	}
+	out := make([]Float, len(x))
+	for i, v := range x {
+		out[i] = Float(v)
+	}
+	return out
+}
+
+func fromFloats(x []Float) []float64 {
+	if x == nil {
+		return nil
+	}
+	out := make([]float64, len(x))
+	for i, v := range x {
+		out[i] = float64(v)
+	}
+	return out
+}
+
+// ReadJSON reads datasets encoded as JSON objects of the form
+// {"name": ..., "x": [...], "y": [...]}, with null for a missing value. The
+// input may hold a single object or an array of them.
+func ReadJSON(r io.Reader) ([]Dataset, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, ErrEmptyInput
+	}
+	var datasets []Dataset
+	if data[0] == '[' {
+		err = json.Unmarshal(data, &datasets)
+	} else {
+		var d Dataset
+		err = json.Unmarshal(data, &d)
+		datasets = []Dataset{d}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(datasets) == 0 {
+		return nil, ErrEmptyInput
+	}
+	return datasets, nil
+}
+
+// ReadNDJSON reads newline-delimited JSON, one dataset object per line, as
+// written by WriteNDJSON. Blank lines are skipped.
+func ReadNDJSON(r io.Reader) ([]Dataset, error) {
+	var datasets []Dataset
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		b := bytes.TrimSpace(scanner.Bytes())
+		if len(b) == 0 {
+			continue
+		}
+		var d Dataset
+		if err := json.Unmarshal(b, &d); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		datasets = append(datasets, d)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(datasets) == 0 {
+		return nil, ErrEmptyInput
+	}
+	return datasets, nil
+}
+
+// WriteJSON writes datasets to w as an indented JSON array.
+func WriteJSON(w io.Writer, datasets []Dataset) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(datasets)
+}
+
+// WriteNDJSON writes datasets to w as newline-delimited JSON.
+func WriteNDJSON(w io.Writer, datasets []Dataset) error {
+	enc := json.NewEncoder(w)
+	for _, d := range datasets {
+		if err := enc.Encode(d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
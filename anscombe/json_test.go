@@ -0,0 +1,66 @@
+package anscombe
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadNDJSON(t *testing.T) {
+	datasets, err := LoadFile("testdata/quartet.ndjson", CSVOptions{})
+	if err != nil {
+		t.Fatalf("LoadFile() returned an error: %v", err)
+	}
+	quartet := Quartet()
+	if len(datasets) != len(quartet) {
+		t.Fatalf("Expected %d sets, got %d", len(quartet), len(datasets))
+	}
+	for i := range quartet {
+		if !reflect.DeepEqual(datasets[i].X, quartet[i].X) || !reflect.DeepEqual(datasets[i].Y, quartet[i].Y) {
+			t.Errorf("Set %s does not match the quartet", datasets[i].Name)
+		}
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	quartet := Quartet()
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, quartet); err != nil {
+		t.Fatalf("WriteJSON() returned an error: %v", err)
+	}
+	datasets, err := ReadJSON(&buf)
+	if err != nil {
+		t.Fatalf("ReadJSON() returned an error: %v", err)
+	}
+	if !reflect.DeepEqual(datasets, quartet) {
+		t.Errorf("ReadJSON() did not return the datasets written by WriteJSON()")
+	}
+
+	buf.Reset()
+	if err := WriteNDJSON(&buf, quartet); err != nil {
+		t.Fatalf("WriteNDJSON() returned an error: %v", err)
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines != len(quartet) {
+		t.Errorf("WriteNDJSON() wrote %d lines, expected %d", lines, len(quartet))
+	}
+	if datasets, err = ReadNDJSON(&buf); err != nil || !reflect.DeepEqual(datasets, quartet) {
+		t.Errorf("ReadNDJSON() did not return the datasets written by WriteNDJSON(): %v", err)
+	}
+}
+
+func TestReadJSONSingleObject(t *testing.T) {
+	datasets, err := ReadJSON(strings.NewReader(`{"name": "line", "x": [1, 2], "y": [3, 4]}`))
+	if err != nil {
+		t.Fatalf("ReadJSON() returned an error: %v", err)
+	}
+	if len(datasets) != 1 || datasets[0].Name != "line" || datasets[0].Y[1] != 4 {
+		t.Errorf("ReadJSON() returned %+v", datasets)
+	}
+	if _, err := ReadJSON(strings.NewReader(" ")); err != ErrEmptyInput {
+		t.Errorf("ReadJSON() with no input: expected %v, got %v", ErrEmptyInput, err)
+	}
+	if _, err := ReadNDJSON(strings.NewReader("{\"name\": \"a\"}\nnot json\n")); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("ReadNDJSON() with a bad line did not report it: %v", err)
+	}
+}
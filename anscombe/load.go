@@ -0,0 +1,35 @@
+package anscombe
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LoadFile reads the datasets stored at path. Files ending in .json are read
+// with ReadJSON and files ending in .ndjson or .jsonl with ReadNDJSON. Anything
+// else is delimited text: files ending in .tsv or .tab are read as
+// tab-separated unless opts.Comma is set, and the single set of an ungrouped
+// file is named after the file if opts.Name is empty.
+func LoadFile(path string, opts CSVOptions) ([]Dataset, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	ext := strings.ToLower(filepath.Ext(path))
+	switch ext {
+	case ".json":
+		return ReadJSON(f)
+	case ".ndjson", ".jsonl":
+		return ReadNDJSON(f)
+	}
+	if opts.Name == "" {
+		opts.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if opts.Comma == 0 && (ext == ".tsv" || ext == ".tab") {
+		opts.Comma = '\t'
+	}
+	return ReadCSV(f, opts)
+}
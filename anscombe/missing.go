@@ -0,0 +1,192 @@
+package anscombe
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/montanaflynn/stats"
+)
+
+// MissingPolicy selects what happens to observations whose x or y is missing,
+// which the loaders read from blank CSV fields and JSON nulls as NaN.
+type MissingPolicy int
+
+const (
+	// FailMissing leaves missing values in place, so that the set fails the
+	// NonFinite rule with ErrNaN.
+	FailMissing MissingPolicy = iota
+	// DropMissing drops every observation missing x or y, pairwise deletion.
+	DropMissing
+	// ImputeMean replaces each missing value with the mean of the values of
+	// its variable that are present.
+	ImputeMean
+	// ImputeMedian replaces each missing value with the median of the values
+	// of its variable that are present.
+	ImputeMedian
+	// Interpolate replaces a missing y by linear interpolation along x
+	// between the nearest observations on either side, averaging those that
+	// share an x. Observations missing x, or with no neighbour on one side,
+	// are dropped.
+	Interpolate
+)
+
+var missingPolicyNames = []string{
+	FailMissing:  "fail",
+	DropMissing:  "drop",
+	ImputeMean:   "mean",
+	ImputeMedian: "median",
+	Interpolate:  "interpolate",
+}
+
+// String returns the name of p, e.g. "drop".
+func (p MissingPolicy) String() string {
+	if p < 0 || int(p) >= len(missingPolicyNames) {
+		return fmt.Sprintf("MissingPolicy(%d)", int(p))
+	}
+	return missingPolicyNames[p]
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (p MissingPolicy) MarshalText() ([]byte, error) {
+	if p < 0 || int(p) >= len(missingPolicyNames) {
+		return nil, fmt.Errorf("invalid missing-value policy %d", int(p))
+	}
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It accepts the names
+// returned by String.
+func (p *MissingPolicy) UnmarshalText(b []byte) error {
+	for i, name := range missingPolicyNames {
+		if name == string(b) {
+			*p = MissingPolicy(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown missing-value policy %q", b)
+}
+
+// Missing records what a MissingPolicy did to the observations of a dataset
+// with missing values.
+type Missing struct {
+	Policy  MissingPolicy `json:"policy"`
+	Missing int           `json:"missing"` // observations missing x, y or both
+	Dropped int           `json:"dropped"` // observations removed
+	Imputed int           `json:"imputed"` // observations kept with a value filled in
+}
+
+// Apply returns d with its missing values handled by p, and a record of the
+// observations it touched. d itself is not modified. Sets of mismatched
+// lengths are returned unchanged, for the Lengths rule to report.
+func (p MissingPolicy) Apply(d Dataset) (Dataset, Missing) {
+	m := Missing{Policy: p}
+	if len(d.X) != len(d.Y) {
+		return d, m
+	}
+	for i := range d.X {
+		if math.IsNaN(d.X[i]) || math.IsNaN(d.Y[i]) {
+			m.Missing++
+		}
+	}
+	if m.Missing == 0 || p == FailMissing {
+		return d, m
+	}
+
+	var fillX, fillY func(i int) (float64, bool)
+	switch p {
+	case ImputeMean, ImputeMedian:
+		fillX, fillY = impute(d.X, p), impute(d.Y, p)
+	case Interpolate:
+		fillY = interpolate(d)
+	}
+	out := Dataset{Name: d.Name, X: make([]float64, 0, len(d.X)), Y: make([]float64, 0, len(d.Y))}
+	for i := range d.X {
+		x, y := d.X[i], d.Y[i]
+		if !math.IsNaN(x) && !math.IsNaN(y) {
+			out.X, out.Y = append(out.X, x), append(out.Y, y)
+			continue
+		}
+		ok := true
+		if math.IsNaN(x) {
+			x, ok = fill(fillX, i)
+		}
+		if ok && math.IsNaN(y) {
+			y, ok = fill(fillY, i)
+		}
+		if !ok {
+			m.Dropped++
+			continue
+		}
+		out.X, out.Y = append(out.X, x), append(out.Y, y)
+		m.Imputed++
+	}
+	return out, m
+}
+
+// fill returns the value f fills in at i, or false if f is nil or has none.
+func fill(f func(i int) (float64, bool), i int) (float64, bool) {
+	if f == nil {
+		return 0, false
+	}
+	return f(i)
+}
+
+// impute returns the mean or median of the values of x that are present, for
+// every index, or none if no value is present.
+func impute(x []float64, p MissingPolicy) func(i int) (float64, bool) {
+	present := make([]float64, 0, len(x))
+	for _, v := range x {
+		if !math.IsNaN(v) {
+			present = append(present, v)
+		}
+	}
+	if len(present) == 0 {
+		return nil
+	}
+	var v float64
+	if p == ImputeMedian {
+		v, _ = stats.Median(present)
+	} else {
+		v, _ = Mean(present)
+	}
+	return func(int) (float64, bool) { return v, true }
+}
+
+// interpolate returns the y of d at index i interpolated linearly along x
+// between the complete observations on either side of x[i].
+func interpolate(d Dataset) func(i int) (float64, bool) {
+	// Complete observations by x, with the y of those sharing an x averaged
+	type knot struct{ x, y float64 }
+	var complete []knot
+	for i := range d.X {
+		if !math.IsNaN(d.X[i]) && !math.IsNaN(d.Y[i]) {
+			complete = append(complete, knot{d.X[i], d.Y[i]})
+		}
+	}
+	sort.Slice(complete, func(a, b int) bool { return complete[a].x < complete[b].x })
+	var knots []knot
+	for lo := 0; lo < len(complete); {
+		hi, sum := lo, 0.0
+		for ; hi < len(complete) && complete[hi].x == complete[lo].x; hi++ {
+			sum += complete[hi].y
+		}
+		knots = append(knots, knot{complete[lo].x, sum / float64(hi-lo)})
+		lo = hi
+	}
+
+	return func(i int) (float64, bool) {
+		x := d.X[i]
+		j := sort.Search(len(knots), func(k int) bool { return knots[k].x >= x })
+		switch {
+		case j == len(knots):
+			return 0, false
+		case knots[j].x == x:
+			return knots[j].y, true
+		case j == 0:
+			return 0, false
+		}
+		lo, hi := knots[j-1], knots[j]
+		return lo.y + (x-lo.x)*(hi.y-lo.y)/(hi.x-lo.x), true
+	}
+}
@@ -0,0 +1,120 @@
+package anscombe
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMissingPolicies(t *testing.T) {
+	nan := math.NaN()
+	d := Dataset{Name: "gaps", X: []float64{1, 2, 3, 4, nan, 6}, Y: []float64{2, nan, 6, 8, 10, nan}}
+	tests := []struct {
+		policy MissingPolicy
+		x, y   []float64
+		want   Missing
+	}{
+		{DropMissing, []float64{1, 3, 4}, []float64{2, 6, 8}, Missing{DropMissing, 3, 3, 0}},
+		{ImputeMean, []float64{1, 2, 3, 4, 3.2, 6}, []float64{2, 6.5, 6, 8, 10, 6.5}, Missing{ImputeMean, 3, 0, 3}},
+		{ImputeMedian, []float64{1, 2, 3, 4, 3, 6}, []float64{2, 7, 6, 8, 10, 7}, Missing{ImputeMedian, 3, 0, 3}},
+		// x = 2 lies between (1, 2) and (3, 6); x = 6 is past the last complete point
+		{Interpolate, []float64{1, 2, 3, 4}, []float64{2, 4, 6, 8}, Missing{Interpolate, 3, 2, 1}},
+	}
+	for _, tt := range tests {
+		got, m := tt.policy.Apply(d)
+		if !reflect.DeepEqual(got.X, tt.x) || !reflect.DeepEqual(got.Y, tt.y) || m != tt.want {
+			t.Errorf("%v.Apply() = %v, %v, %+v, expected %v, %v, %+v", tt.policy, got.X, got.Y, m, tt.x, tt.y, tt.want)
+		}
+	}
+	if !math.IsNaN(d.Y[1]) {
+		t.Error("Apply() modified its argument")
+	}
+
+	// Test case 2: failing leaves the values for the rules to reject
+	got, m := FailMissing.Apply(d)
+	if len(got.X) != 6 || m != (Missing{FailMissing, 3, 0, 0}) {
+		t.Errorf("FailMissing.Apply() = %v, %+v", got, m)
+	}
+
+	// Test case 3: interpolation averages the y of observations sharing an x
+	tied := Dataset{X: []float64{8, 8, 10, 9}, Y: []float64{1, 3, 5, nan}}
+	if got, _ := Interpolate.Apply(tied); got.Y[3] != 3.5 {
+		t.Errorf("Interpolate.Apply() at x = 9 = %g, expected 3.5", got.Y[3])
+	}
+}
+
+func TestSummarizeMissing(t *testing.T) {
+	d := Quartet()[0]
+	d.Y = append([]float64(nil), d.Y...)
+	d.Y[3] = math.NaN()
+
+	// Test case 1: the default is to fail
+	if _, err := Summarize(d, DefaultOptions()); !errors.Is(err, ErrNaN) {
+		t.Errorf("Summarize() with a missing y: expected %v, got %v", ErrNaN, err)
+	}
+	results := Analyze([]Dataset{d}, DefaultOptions())
+	if m := results.Sets[0].Missing; m == nil || m.Missing != 1 || m.Dropped != 0 {
+		t.Errorf("Analyze() of a failed set recorded missing values %+v", m)
+	}
+
+	// Test case 2: dropping analyzes the ten complete observations
+	opts := DefaultOptions()
+	opts.Missing = DropMissing
+	s, err := Summarize(d, opts)
+	if err != nil {
+		t.Fatalf("Summarize() with DropMissing returned an error: %v", err)
+	}
+	if s.N != 10 || s.Missing == nil || *s.Missing != (Missing{DropMissing, 1, 1, 0}) {
+		t.Errorf("Summarize() with DropMissing: N = %d, Missing = %+v", s.N, s.Missing)
+	}
+	var buf strings.Builder
+	if err := WriteReport(&buf, Analyze([]Dataset{d}, opts), Text, DefaultPrecision(Text)); err != nil {
+		t.Fatalf("WriteReport() returned an error: %v", err)
+	}
+	if want := "  Missing x or y: 1, handled by drop: 1 dropped, 0 imputed\n"; !strings.Contains(buf.String(), want) {
+		t.Errorf("Text report is missing %q:\n%s", want, buf.String())
+	}
+
+	// Test case 3: a complete set records nothing
+	if s, _ := Summarize(Quartet()[0], opts); s.Missing != nil {
+		t.Errorf("Summarize() of a complete set recorded missing values %+v", s.Missing)
+	}
+}
+
+func TestLoadMissing(t *testing.T) {
+	// Test case 1: blank and NaN fields of a CSV file are missing
+	datasets, err := ReadCSV(strings.NewReader("x,y\n1,2\n2,\n,6\n4,NaN\n"), CSVOptions{})
+	if err != nil {
+		t.Fatalf("ReadCSV() with blanks returned an error: %v", err)
+	}
+	d := datasets[0]
+	if !math.IsNaN(d.Y[1]) || !math.IsNaN(d.X[2]) || !math.IsNaN(d.Y[3]) || d.X[3] != 4 {
+		t.Errorf("ReadCSV() with blanks = %+v", d)
+	}
+
+	// Test case 2: JSON null is missing, and missing values are written as null
+	datasets, err = ReadJSON(strings.NewReader(`{"name": "a", "x": [1, null, 3], "y": [1, 2, 3]}`))
+	if err != nil || !math.IsNaN(datasets[0].X[1]) {
+		t.Fatalf("ReadJSON() with null = %v, %v", datasets, err)
+	}
+	var buf strings.Builder
+	if err := WriteNDJSON(&buf, datasets); err != nil || buf.String() != `{"name":"a","x":[1,null,3],"y":[1,2,3]}`+"\n" {
+		t.Errorf("WriteNDJSON() with NaN = %q, %v", buf.String(), err)
+	}
+}
+
+func TestMissingPolicyText(t *testing.T) {
+	for _, p := range []MissingPolicy{FailMissing, DropMissing, ImputeMean, ImputeMedian, Interpolate} {
+		b, err := p.MarshalText()
+		var back MissingPolicy
+		if err != nil || back.UnmarshalText(b) != nil || back != p {
+			t.Errorf("MissingPolicy %v did not round trip: %s, %v", p, b, err)
+		}
+	}
+	var p MissingPolicy
+	if err := p.UnmarshalText([]byte("zero")); err == nil {
+		t.Error("UnmarshalText(zero): expected an error")
+	}
+}
@@ -0,0 +1,46 @@
+package anscombe
+
+// Options controls how datasets are summarized.
+type Options struct {
+	Missing MissingPolicy // how observations missing x or y are handled, before the rules
+	Rules   []Rule        // data-quality rules checked first; a set with a critical finding is not analyzed
+
+	DDOF   DDOF        // variance convention for descriptive statistics
+	Level  float64     // confidence level of intervals, 0.95 if zero
+	Robust []Estimator // estimators compared with the least squares line
+	Degree int         // degree of the polynomial compared with the line, none if below 2
+
+	Measures     []Measure // association measures reported next to the fit
+	Permutations int       // permutations of the test of each measure, none if zero
+	Seed         int64     // seed of random resampling, so that results are reproducible
+
+	Resampling Resampling // how bootstrap samples are drawn
+	Replicates int        // bootstrap replicates, no bootstrap if zero
+	Workers    int        // goroutines drawing bootstrap replicates, runtime.GOMAXPROCS(0) if zero
+}
+
+// DefaultOptions returns the options that check DefaultRules and reproduce
+// Anscombe's published table, sample variances and 95% confidence intervals,
+// and compares the least squares line with DefaultEstimators and a quadratic.
+// It reports DefaultMeasures, each tested with 999 permutations, and
+// bootstraps the fit with 1999 resampled pairs.
+func DefaultOptions() Options {
+	return Options{
+		Rules:        DefaultRules(),
+		DDOF:         Sample,
+		Level:        0.95,
+		Robust:       DefaultEstimators(),
+		Degree:       2,
+		Measures:     DefaultMeasures(),
+		Permutations: 999,
+		Resampling:   PairsBootstrap,
+		Replicates:   1999,
+	}
+}
+
+func (o Options) level() float64 {
+	if o.Level == 0 {
+		return 0.95
+	}
+	return o.Level
+}
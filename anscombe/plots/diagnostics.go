@@ -0,0 +1,201 @@
+package plots
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/bilguunbilegt/automated_programming/anscombe"
+	"gonum.org/v1/gonum/stat/distuv"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// cookLevels are the Cook's distances drawn as contours on the residuals vs
+// leverage panel.
+var cookLevels = []float64{0.5, 1}
+
+// Diagnostics returns the four standard regression diagnostic panels of the
+// OLS fit to d as a 2x2 grid for SaveFacet:
+//
+//	residuals vs fitted      normal Q-Q of standardized residuals
+//	scale-location           standardized residuals vs leverage
+//
+// The leverage panel carries dashed Cook's distance contours at 0.5 and 1.
+// Points flagged by anscombe.Fit.Influence are ringed in every panel. Points
+// with leverage one, whose standardized residuals are undefined, are left out
+// of the last three panels.
+func Diagnostics(d anscombe.Dataset) ([][]*plot.Plot, error) {
+	fit, err := anscombe.LinearRegression(d.X, d.Y)
+	if err != nil {
+		return nil, err
+	}
+	if fit.DF < 1 {
+		return nil, anscombe.ErrTooFew
+	}
+	std := fit.StandardizedResiduals()
+	flagged := make([]bool, fit.N())
+	if fit.DF > 1 {
+		if flagged, err = influential(fit, d.X); err != nil {
+			return nil, err
+		}
+	}
+
+	// defined and definedFlags hold the points with a standardized residual.
+	var residuals, scale, leverage plotter.XYs
+	var defined []int
+	for i := range fit.Residuals {
+		residuals = append(residuals, plotter.XY{X: fit.Fitted[i], Y: fit.Residuals[i]})
+		if math.IsNaN(std[i]) {
+			continue
+		}
+		scale = append(scale, plotter.XY{X: fit.Fitted[i], Y: math.Sqrt(math.Abs(std[i]))})
+		leverage = append(leverage, plotter.XY{X: fit.Leverage[i], Y: std[i]})
+		defined = append(defined, i)
+	}
+	definedFlags := make([]bool, len(defined))
+	for j, i := range defined {
+		definedFlags[j] = flagged[i]
+	}
+	sorted := append([]int(nil), defined...)
+	sort.Slice(sorted, func(a, b int) bool { return std[sorted[a]] < std[sorted[b]] })
+	qq := make(plotter.XYs, len(sorted))
+	qqFlags := make([]bool, len(sorted))
+	for j, i := range sorted {
+		qq[j] = plotter.XY{X: distuv.UnitNormal.Quantile(plottingPosition(j+1, len(sorted))), Y: std[i]}
+		qqFlags[j] = flagged[i]
+	}
+
+	panels := [][]*plot.Plot{{nil, nil}, {nil, nil}}
+	if panels[0][0], err = diagnosticPanel(d.Name+": Residuals vs Fitted", "Fitted values", "Residuals", residuals); err != nil {
+		return nil, err
+	}
+	panels[0][0].Add(horizontal(0))
+	if panels[0][1], err = diagnosticPanel(d.Name+": Normal Q-Q", "Theoretical quantiles", "Standardized residuals", qq); err != nil {
+		return nil, err
+	}
+	if len(qq) > 0 {
+		identity, err := dashedLine(plotter.XYs{{X: qq[0].X, Y: qq[0].X}, {X: qq[len(qq)-1].X, Y: qq[len(qq)-1].X}})
+		if err != nil {
+			return nil, err
+		}
+		panels[0][1].Add(identity)
+	}
+	if panels[1][0], err = diagnosticPanel(d.Name+": Scale-Location", "Fitted values", "√|Standardized residuals|", scale); err != nil {
+		return nil, err
+	}
+	if panels[1][1], err = diagnosticPanel(d.Name+": Residuals vs Leverage", "Leverage", "Standardized residuals", leverage); err != nil {
+		return nil, err
+	}
+	panels[1][1].Add(horizontal(0))
+	if err := addCookContours(panels[1][1], leverage); err != nil {
+		return nil, err
+	}
+	for _, r := range []struct {
+		p       *plot.Plot
+		pts     plotter.XYs
+		flagged []bool
+	}{
+		{panels[0][0], residuals, flagged},
+		{panels[0][1], qq, qqFlags},
+		{panels[1][0], scale, definedFlags},
+		{panels[1][1], leverage, definedFlags},
+	} {
+		if err := addInfluenceRings(r.p, r.pts, r.flagged); err != nil {
+			return nil, err
+		}
+	}
+	return panels, nil
+}
+
+// plottingPosition returns the probability at which the i-th of n ordered
+// values is plotted on a Q-Q plot, using the same rule as R's ppoints.
+func plottingPosition(i, n int) float64 {
+	a := 0.5
+	if n <= 10 {
+		a = 3.0 / 8
+	}
+	return (float64(i) - a) / (float64(n) + 1 - 2*a)
+}
+
+func diagnosticPanel(title, xLabel, yLabel string, pts plotter.XYs) (*plot.Plot, error) {
+	p := plot.New()
+	p.Title.Text = title
+	p.X.Label.Text = xLabel
+	p.Y.Label.Text = yLabel
+	if len(pts) == 0 {
+		return p, nil
+	}
+	s, err := plotter.NewScatter(pts)
+	if err != nil {
+		return nil, err
+	}
+	s.GlyphStyle.Shape = draw.CircleGlyph{}
+	s.GlyphStyle.Color = pointColor
+	p.Add(s)
+	return p, nil
+}
+
+// horizontal returns a dashed reference line at y across the whole panel.
+func horizontal(y float64) plot.Plotter {
+	f := plotter.NewFunction(func(float64) float64 { return y })
+	f.Dashes = []vg.Length{vg.Points(3), vg.Points(3)}
+	f.Color = predictionColor
+	return f
+}
+
+func dashedLine(pts plotter.XYs) (*plotter.Line, error) {
+	l, err := plotter.NewLine(pts)
+	if err != nil {
+		return nil, err
+	}
+	l.Dashes = []vg.Length{vg.Points(3), vg.Points(3)}
+	return l, nil
+}
+
+// addCookContours draws the standardized residuals at which a point of
+// leverage h reaches each Cook's distance D: r = ±sqrt(D p (1-h) / h), with
+// p = 2 coefficients.
+func addCookContours(p *plot.Plot, pts plotter.XYs) error {
+	if len(pts) == 0 {
+		return nil
+	}
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, pt := range pts {
+		lo, hi = math.Min(lo, pt.X), math.Max(hi, pt.X)
+	}
+	// Keep the contours inside the range of the points so they do not
+	// stretch the panel towards h = 0, where they diverge.
+	yLimit := math.Max(math.Abs(p.Y.Min), math.Abs(p.Y.Max)) * 1.5
+	for _, level := range cookLevels {
+		for _, sign := range []float64{1, -1} {
+			var curve plotter.XYs
+			for i := 0; i < gridPoints; i++ {
+				h := lo + (hi-lo)*float64(i)/float64(gridPoints-1)
+				if h <= 0 || h >= 1 {
+					continue
+				}
+				r := sign * math.Sqrt(level*2*(1-h)/h)
+				if math.Abs(r) <= yLimit {
+					curve = append(curve, plotter.XY{X: h, Y: r})
+				}
+			}
+			if len(curve) < 2 {
+				continue
+			}
+			l, err := dashedLine(curve)
+			if err != nil {
+				return err
+			}
+			l.Color = lineColor
+			p.Add(l)
+			if sign > 0 {
+				p.Legend.Add(fmt.Sprintf("Cook's distance %g", level), l)
+			}
+		}
+	}
+	p.Legend.Top = true
+	return nil
+}
@@ -0,0 +1,52 @@
+package plots
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/bilguunbilegt/automated_programming/anscombe"
+	"gonum.org/v1/plot/vg"
+)
+
+func TestDiagnostics(t *testing.T) {
+	for _, d := range anscombe.Quartet() {
+		panels, err := Diagnostics(d)
+		if err != nil {
+			t.Fatalf("Diagnostics(%s) returned an error: %v", d.Name, err)
+		}
+		titles := []string{"Residuals vs Fitted", "Normal Q-Q", "Scale-Location", "Residuals vs Leverage"}
+		for i, title := range titles {
+			if got := panels[i/2][i%2].Title.Text; got != d.Name+": "+title {
+				t.Errorf("Panel %d: expected title %q, got %q", i, d.Name+": "+title, got)
+			}
+		}
+		if err := SaveFacet(panels, 8*vg.Inch, 8*vg.Inch, filepath.Join(t.TempDir(), "diagnostics.png")); err != nil {
+			t.Errorf("SaveFacet(%s) returned an error: %v", d.Name, err)
+		}
+	}
+
+	// Set 3's outlier stands out on the Q-Q plot.
+	panels, _ := Diagnostics(anscombe.Quartet()[2])
+	if qq := panels[0][1]; qq.Y.Max < 2.5 {
+		t.Errorf("Q-Q plot of set 3 tops out at %g, expected the outlier above 2.5", qq.Y.Max)
+	}
+	// Set 4's leverage-one point is left out instead of plotted at infinity.
+	panels, _ = Diagnostics(anscombe.Quartet()[3])
+	if lev := panels[1][1]; math.IsInf(lev.Y.Max, 0) || lev.X.Max >= 1 {
+		t.Errorf("Residuals vs leverage of set 4 spans x up to %g and y up to %g", lev.X.Max, lev.Y.Max)
+	}
+}
+
+func TestPlottingPosition(t *testing.T) {
+	// ppoints(5) in R: 0.1190476 0.3095238 0.5000000 0.6904762 0.8809524
+	want := []float64{0.1190476, 0.3095238, 0.5, 0.6904762, 0.8809524}
+	for i, w := range want {
+		if got := plottingPosition(i+1, 5); math.Abs(got-w) > 1e-7 {
+			t.Errorf("plottingPosition(%d, 5) = %g, expected %g", i+1, got, w)
+		}
+	}
+	if got := plottingPosition(1, 20); math.Abs(got-0.025) > 1e-12 {
+		t.Errorf("plottingPosition(1, 20) = %g, expected 0.025", got)
+	}
+}
@@ -0,0 +1,141 @@
+package plots
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bilguunbilegt/automated_programming/anscombe"
+	"github.com/montanaflynn/stats"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// FacetOptions controls the layout of a faceted figure.
+type FacetOptions struct {
+	Columns  int            // panels per row, ceil(sqrt(n)) if zero
+	Scatter  ScatterOptions // overlays drawn in every panel
+	Annotate bool           // print the means, r and the fitted line in each panel
+}
+
+// DefaultFacetOptions lays the quartet out as the classic 2x2 figure with
+// annotated panels, the fitted line and ringed influential points.
+func DefaultFacetOptions() FacetOptions {
+	return FacetOptions{Scatter: ScatterOptions{Line: true, Influential: true}, Annotate: true}
+}
+
+// Facet returns one scatter panel per dataset, titled with its name, laid out
+// in rows of opts.Columns. All panels share the same x and y limits so the
+// sets can be compared by eye. Cells after the last dataset are nil. Only the
+// first panel keeps its legend, and none do when the panels are annotated.
+func Facet(datasets []anscombe.Dataset, opts FacetOptions) ([][]*plot.Plot, error) {
+	if len(datasets) == 0 {
+		return nil, anscombe.ErrEmptyInput
+	}
+	cols := opts.Columns
+	if cols <= 0 {
+		cols = int(math.Ceil(math.Sqrt(float64(len(datasets)))))
+	}
+	rows := (len(datasets) + cols - 1) / cols
+
+	panels := make([]*plot.Plot, len(datasets))
+	xMin, xMax := math.Inf(1), math.Inf(-1)
+	yMin, yMax := math.Inf(1), math.Inf(-1)
+	for i, d := range datasets {
+		p, err := Scatter(d, opts.Scatter)
+		if err != nil {
+			return nil, &anscombe.SetError{Set: d.Name, Err: err}
+		}
+		if i > 0 || opts.Annotate {
+			p.Legend = plot.NewLegend()
+		}
+		xMin, xMax = math.Min(xMin, p.X.Min), math.Max(xMax, p.X.Max)
+		yMin, yMax = math.Min(yMin, p.Y.Min), math.Max(yMax, p.Y.Max)
+		panels[i] = p
+	}
+
+	grid := make([][]*plot.Plot, rows)
+	for r := range grid {
+		grid[r] = make([]*plot.Plot, cols)
+	}
+	for i, p := range panels {
+		p.X.Min, p.X.Max = xMin, xMax
+		p.Y.Min, p.Y.Max = yMin, yMax
+		if opts.Annotate {
+			if err := annotate(p, datasets[i], xMin, yMax); err != nil {
+				return nil, &anscombe.SetError{Set: datasets[i].Name, Err: err}
+			}
+		}
+		grid[i/cols][i%cols] = p
+	}
+	return grid, nil
+}
+
+// annotate writes the means, correlation and fitted line of d in the top
+// left corner of p, at (x, y) in data coordinates.
+func annotate(p *plot.Plot, d anscombe.Dataset, x, y float64) error {
+	fit, err := anscombe.LinearRegression(d.X, d.Y)
+	if err != nil {
+		return err
+	}
+	r, err := stats.Correlation(d.X, d.Y)
+	if err != nil {
+		return err
+	}
+	meanX, _ := anscombe.Mean(d.X)
+	meanY, _ := anscombe.Mean(d.Y)
+	f := anscombe.DefaultPrecision(anscombe.Text).Format
+	text := fmt.Sprintf("mean x = %s, mean y = %s\nr = %s\ny = %s + %sx",
+		f(meanX), f(meanY), f(r), f(fit.Intercept), f(fit.Slope))
+	labels, err := plotter.NewLabels(plotter.XYLabels{XYs: plotter.XYs{{X: x, Y: y}}, Labels: []string{text}})
+	if err != nil {
+		return err
+	}
+	labels.TextStyle[0].XAlign = draw.XLeft
+	labels.TextStyle[0].YAlign = draw.YTop
+	labels.TextStyle[0].Font.Size = vg.Points(8)
+	labels.Offset = vg.Point{X: vg.Points(4), Y: -vg.Points(4)}
+	p.Add(labels)
+	return nil
+}
+
+// SaveFacet draws a grid of panels, such as those returned by Facet or
+// Diagnostics, into one width by height figure at path. The image format
+// follows the extension of path.
+func SaveFacet(panels [][]*plot.Plot, width, height vg.Length, path string) error {
+	if len(panels) == 0 {
+		return anscombe.ErrEmptyInput
+	}
+	format := strings.ToLower(strings.TrimPrefix(filepath.Ext(path), "."))
+	c, err := draw.NewFormattedCanvas(width, height, format)
+	if err != nil {
+		return err
+	}
+	tiles := draw.Tiles{
+		Rows: len(panels), Cols: len(panels[0]),
+		PadX: vg.Millimeter, PadY: vg.Millimeter,
+		PadTop: vg.Points(2), PadBottom: vg.Points(2), PadLeft: vg.Points(2), PadRight: vg.Points(2),
+	}
+	canvases := plot.Align(panels, tiles, draw.New(c))
+	for j, row := range panels {
+		for i, p := range row {
+			if p != nil {
+				p.Draw(canvases[j][i])
+			}
+		}
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := c.WriteTo(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
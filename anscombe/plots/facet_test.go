@@ -0,0 +1,65 @@
+package plots
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bilguunbilegt/automated_programming/anscombe"
+	"gonum.org/v1/plot/vg"
+)
+
+func TestFacetQuartet(t *testing.T) {
+	panels, err := Facet(anscombe.Quartet(), DefaultFacetOptions())
+	if err != nil {
+		t.Fatalf("Facet() returned an error: %v", err)
+	}
+	if len(panels) != 2 || len(panels[0]) != 2 || len(panels[1]) != 2 {
+		t.Fatalf("Expected a 2x2 grid, got %d rows", len(panels))
+	}
+	first := panels[0][0]
+	for r, row := range panels {
+		for c, p := range row {
+			if want := anscombe.Quartet()[r*2+c].Name; p.Title.Text != want {
+				t.Errorf("Panel (%d, %d): expected title %q, got %q", r, c, want, p.Title.Text)
+			}
+			if p.X.Min != first.X.Min || p.X.Max != first.X.Max || p.Y.Min != first.Y.Min || p.Y.Max != first.Y.Max {
+				t.Errorf("Panel (%d, %d) does not share the axis limits of the first panel", r, c)
+			}
+		}
+	}
+	// Set 4 reaches x = 19 and set 3 y = 12.74, so every panel shows both.
+	if first.X.Max < 19 || first.Y.Max < 12.74 {
+		t.Errorf("Shared limits [%g, %g] x [%g, %g] do not cover all sets", first.X.Min, first.X.Max, first.Y.Min, first.Y.Max)
+	}
+
+	path := filepath.Join(t.TempDir(), "anscombe.png")
+	if err := SaveFacet(panels, 8*vg.Inch, 8*vg.Inch, path); err != nil {
+		t.Fatalf("SaveFacet() returned an error: %v", err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Size() == 0 {
+		t.Errorf("SaveFacet() did not write the figure: %v", err)
+	}
+}
+
+func TestFacetLayout(t *testing.T) {
+	datasets := append(anscombe.Quartet(), anscombe.Quartet()[0])
+	datasets[4].Name = "Set 5"
+	panels, err := Facet(datasets, FacetOptions{Columns: 3})
+	if err != nil {
+		t.Fatalf("Facet() returned an error: %v", err)
+	}
+	if len(panels) != 2 || len(panels[1]) != 3 {
+		t.Fatalf("Expected 2 rows of 3 panels, got %d rows", len(panels))
+	}
+	if panels[1][1] == nil || panels[1][1].Title.Text != "Set 5" || panels[1][2] != nil {
+		t.Errorf("Expected Set 5 in the middle of the last row followed by an empty cell")
+	}
+	if err := SaveFacet(panels, 9*vg.Inch, 6*vg.Inch, filepath.Join(t.TempDir(), "five.svg")); err != nil {
+		t.Errorf("SaveFacet() with an empty cell returned an error: %v", err)
+	}
+
+	if _, err := Facet(nil, FacetOptions{}); err != anscombe.ErrEmptyInput {
+		t.Errorf("Facet() of no datasets: expected %v, got %v", anscombe.ErrEmptyInput, err)
+	}
+}
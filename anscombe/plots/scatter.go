@@ -0,0 +1,241 @@
+// Package plots draws the Anscombe analysis with gonum/plot. It is kept apart
+// from package anscombe so that services using only the statistics do not
+// pull in the plotting dependencies.
+package plots
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/bilguunbilegt/automated_programming/anscombe"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// ScatterOptions selects what is drawn on top of the points of a scatter
+// plot.
+type ScatterOptions struct {
+	Line           bool    // the fitted OLS line
+	ConfidenceBand bool    // confidence band for the mean response
+	PredictionBand bool    // prediction band for new observations
+	Level          float64 // confidence level of the bands, 0.95 if zero
+	Influential    bool    // ring the points flagged by anscombe.Fit.Influence
+	Degree         int     // degree of a least squares polynomial curve, none if below 2
+}
+
+// DefaultScatterOptions draws the fitted line with both 95% bands and a
+// quadratic curve, and rings the influential points.
+func DefaultScatterOptions() ScatterOptions {
+	return ScatterOptions{Line: true, ConfidenceBand: true, PredictionBand: true, Level: 0.95, Influential: true, Degree: 2}
+}
+
+var (
+	pointColor      = color.NRGBA{R: 31, G: 119, B: 180, A: 255}
+	lineColor       = color.NRGBA{R: 214, G: 39, B: 40, A: 255}
+	confidenceColor = color.NRGBA{R: 214, G: 39, B: 40, A: 70}
+	predictionColor = color.NRGBA{R: 127, G: 127, B: 127, A: 50}
+	influenceColor  = color.NRGBA{R: 255, G: 127, B: 14, A: 255}
+	curveColor      = color.NRGBA{R: 148, G: 103, B: 189, A: 255}
+)
+
+// gridPoints is the number of x values the line and bands are evaluated at.
+const gridPoints = 100
+
+// Scatter returns a scatter plot of d titled with its name, with the overlays
+// selected by opts. Bands need at least three points.
+func Scatter(d anscombe.Dataset, opts ScatterOptions) (*plot.Plot, error) {
+	if err := anscombe.CheckDataQuality(d.X, d.Y); err != nil {
+		return nil, err
+	}
+	p := plot.New()
+	p.Title.Text = d.Name
+	p.X.Label.Text = "X"
+	p.Y.Label.Text = "Y"
+	if err := addScatter(p, d, opts); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// addScatter draws the bands, line, polynomial curve, points and influence
+// rings of d on p, in that order so the points stay on top.
+func addScatter(p *plot.Plot, d anscombe.Dataset, opts ScatterOptions) error {
+	var fit anscombe.Fit
+	if opts.Line || opts.ConfidenceBand || opts.PredictionBand || opts.Influential {
+		var err error
+		if fit, err = anscombe.LinearRegression(d.X, d.Y); err != nil {
+			return err
+		}
+		if err := addFit(p, d, fit, opts); err != nil {
+			return err
+		}
+	}
+	if opts.Degree >= 2 {
+		if err := addPolynomial(p, d, opts.Degree); err != nil {
+			return err
+		}
+	}
+	pts := make(plotter.XYs, d.Len())
+	for i := range d.X {
+		pts[i].X = d.X[i]
+		pts[i].Y = d.Y[i]
+	}
+	s, err := plotter.NewScatter(pts)
+	if err != nil {
+		return err
+	}
+	s.GlyphStyle.Shape = draw.CircleGlyph{}
+	s.GlyphStyle.Color = pointColor
+	p.Add(s)
+	if opts.Influential && fit.DF > 1 {
+		flagged, err := influential(fit, d.X)
+		if err != nil {
+			return err
+		}
+		return addInfluenceRings(p, pts, flagged)
+	}
+	return nil
+}
+
+// influential reports which observations of fit, fitted to x, are flagged by
+// at least one influence measure.
+func influential(fit anscombe.Fit, x []float64) ([]bool, error) {
+	measures, err := fit.Influence(x)
+	if err != nil {
+		return nil, err
+	}
+	flagged := make([]bool, len(measures))
+	for i, m := range measures {
+		flagged[i] = len(m.Flags) > 0
+	}
+	return flagged, nil
+}
+
+// addInfluenceRings rings the points of pts that are flagged, and adds a
+// legend entry when any are.
+func addInfluenceRings(p *plot.Plot, pts plotter.XYs, flagged []bool) error {
+	var rings plotter.XYs
+	for i, f := range flagged {
+		if f {
+			rings = append(rings, pts[i])
+		}
+	}
+	if len(rings) == 0 {
+		return nil
+	}
+	s, err := plotter.NewScatter(rings)
+	if err != nil {
+		return err
+	}
+	s.GlyphStyle.Shape = draw.RingGlyph{}
+	s.GlyphStyle.Color = influenceColor
+	s.GlyphStyle.Radius = vg.Points(6)
+	p.Add(s)
+	p.Legend.Add("influential point", s)
+	return nil
+}
+
+func addFit(p *plot.Plot, d anscombe.Dataset, fit anscombe.Fit, opts ScatterOptions) error {
+	level := opts.Level
+	if level == 0 {
+		level = 0.95
+	}
+	xs := grid(d.X)
+	if opts.PredictionBand {
+		band, err := newBand(xs, func(x float64) (float64, float64, error) { return fit.PredictionInterval(x, level) })
+		if err != nil {
+			return err
+		}
+		band.Color = predictionColor
+		p.Add(band)
+		p.Legend.Add(fmt.Sprintf("%g%% prediction band", level*100), band)
+	}
+	if opts.ConfidenceBand {
+		band, err := newBand(xs, func(x float64) (float64, float64, error) { return fit.MeanInterval(x, level) })
+		if err != nil {
+			return err
+		}
+		band.Color = confidenceColor
+		p.Add(band)
+		p.Legend.Add(fmt.Sprintf("%g%% confidence band", level*100), band)
+	}
+	if opts.Line {
+		line, err := plotter.NewLine(plotter.XYs{{X: xs[0], Y: fit.Predict(xs[0])}, {X: xs[len(xs)-1], Y: fit.Predict(xs[len(xs)-1])}})
+		if err != nil {
+			return err
+		}
+		line.Color = lineColor
+		line.Width = vg.Points(1.5)
+		p.Add(line)
+		p.Legend.Add(fmt.Sprintf("y = %.3g + %.3gx", fit.Intercept, fit.Slope), line)
+	}
+	p.Legend.Top = true
+	p.Legend.Left = true
+	return nil
+}
+
+// addPolynomial draws the least squares polynomial of the given degree. It
+// draws nothing when the points cannot determine it, as set 4's two distinct
+// x values cannot determine a quadratic.
+func addPolynomial(p *plot.Plot, d anscombe.Dataset, degree int) error {
+	fit, err := anscombe.FitBasis(d.X, d.Y, anscombe.PolynomialBasis(degree))
+	if errors.Is(err, anscombe.ErrBounds) || errors.Is(err, anscombe.ErrTooFew) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	xs := grid(d.X)
+	pts := make(plotter.XYs, len(xs))
+	for i, x := range xs {
+		pts[i] = plotter.XY{X: x, Y: fit.Predict(x)}
+	}
+	curve, err := plotter.NewLine(pts)
+	if err != nil {
+		return err
+	}
+	curve.Color = curveColor
+	curve.Width = vg.Points(1.5)
+	curve.Dashes = []vg.Length{vg.Points(6), vg.Points(3)}
+	p.Add(curve)
+	p.Legend.Add(fmt.Sprintf("degree %d fit", degree), curve)
+	return nil
+}
+
+// grid returns evenly spaced values spanning the range of x.
+func grid(x []float64) []float64 {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range x {
+		lo, hi = math.Min(lo, v), math.Max(hi, v)
+	}
+	xs := make([]float64, gridPoints)
+	for i := range xs {
+		xs[i] = lo + (hi-lo)*float64(i)/float64(gridPoints-1)
+	}
+	return xs
+}
+
+// newBand returns a filled polygon between the lower and upper bounds that
+// interval gives at each of xs.
+func newBand(xs []float64, interval func(float64) (float64, float64, error)) (*plotter.Polygon, error) {
+	lower := make(plotter.XYs, len(xs))
+	upper := make(plotter.XYs, len(xs))
+	for i, x := range xs {
+		lo, hi, err := interval(x)
+		if err != nil {
+			return nil, err
+		}
+		lower[i] = plotter.XY{X: x, Y: lo}
+		upper[len(xs)-1-i] = plotter.XY{X: x, Y: hi}
+	}
+	band, err := plotter.NewPolygon(append(lower, upper...))
+	if err != nil {
+		return nil, err
+	}
+	band.LineStyle.Width = 0
+	return band, nil
+}
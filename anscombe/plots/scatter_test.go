@@ -0,0 +1,86 @@
+package plots
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bilguunbilegt/automated_programming/anscombe"
+	"gonum.org/v1/plot/vg"
+)
+
+func TestScatterBands(t *testing.T) {
+	d := anscombe.Quartet()[2]
+	bare, err := Scatter(d, ScatterOptions{})
+	if err != nil {
+		t.Fatalf("Scatter() returned an error: %v", err)
+	}
+	banded, err := Scatter(d, DefaultScatterOptions())
+	if err != nil {
+		t.Fatalf("Scatter() returned an error: %v", err)
+	}
+	if banded.Title.Text != d.Name {
+		t.Errorf("Expected title %q, got %q", d.Name, banded.Title.Text)
+	}
+	// The prediction band reaches beyond the points.
+	if banded.Y.Min >= bare.Y.Min || banded.Y.Max <= bare.Y.Max {
+		t.Errorf("Y range [%g, %g] does not include the prediction band around [%g, %g]",
+			banded.Y.Min, banded.Y.Max, bare.Y.Min, bare.Y.Max)
+	}
+
+	path := filepath.Join(t.TempDir(), "set3.png")
+	if err := banded.Save(5*vg.Inch, 5*vg.Inch, path); err != nil {
+		t.Fatalf("Save() returned an error: %v", err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Size() == 0 {
+		t.Errorf("Save() did not write the plot: %v", err)
+	}
+}
+
+func TestScatterErrors(t *testing.T) {
+	if _, err := Scatter(anscombe.Dataset{Name: "empty"}, ScatterOptions{}); !errors.Is(err, anscombe.ErrEmptyInput) {
+		t.Errorf("Scatter() of an empty set: expected %v, got %v", anscombe.ErrEmptyInput, err)
+	}
+	two := anscombe.Dataset{Name: "two", X: []float64{1, 2}, Y: []float64{1, 3}}
+	if _, err := Scatter(two, ScatterOptions{Line: true}); err != nil {
+		t.Errorf("Scatter() with a line through two points returned an error: %v", err)
+	}
+	if _, err := Scatter(two, DefaultScatterOptions()); err != anscombe.ErrTooFew {
+		t.Errorf("Scatter() with bands around two points: expected %v, got %v", anscombe.ErrTooFew, err)
+	}
+}
+
+func TestInfluential(t *testing.T) {
+	d := anscombe.Quartet()[3]
+	fit, err := anscombe.LinearRegression(d.X, d.Y)
+	if err != nil {
+		t.Fatalf("LinearRegression() returned an error: %v", err)
+	}
+	flagged, err := influential(fit, d.X)
+	if err != nil {
+		t.Fatalf("influential() returned an error: %v", err)
+	}
+	for i, f := range flagged {
+		if f != (d.X[i] == 19) {
+			t.Errorf("Point %d (x = %g): flagged %v", i, d.X[i], f)
+		}
+	}
+	if _, err := Scatter(d, ScatterOptions{Influential: true}); err != nil {
+		t.Errorf("Scatter() ringing influential points returned an error: %v", err)
+	}
+}
+
+func TestScatterPolynomial(t *testing.T) {
+	d := anscombe.Quartet()[1]
+	if _, err := Scatter(d, ScatterOptions{Degree: 2}); err != nil {
+		t.Fatalf("Scatter() returned an error: %v", err)
+	}
+	// Set 4 cannot determine a quadratic, so none is drawn.
+	if _, err := Scatter(anscombe.Quartet()[3], ScatterOptions{Degree: 2}); err != nil {
+		t.Errorf("Scatter() of set 4 with a quadratic returned an error: %v", err)
+	}
+	if _, err := Scatter(d, ScatterOptions{Degree: 11}); err != nil {
+		t.Errorf("Scatter() with more coefficients than points returned an error: %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+package anscombe
+
+import (
+	"math"
+	"strconv"
+)
+
+// Precision controls how many digits the report layer keeps. Statistics are
+// always computed at full precision; rounding happens only when a report is
+// written.
+type Precision struct {
+	Digits      int  // digits to keep; 0 keeps full precision
+	Significant bool // count significant figures instead of decimal places
+}
+
+// Round rounds x to p.
+func (p Precision) Round(x float64) float64 {
+	if p.Digits <= 0 || x == 0 || math.IsNaN(x) || math.IsInf(x, 0) {
+		return x
+	}
+	return Round(x, p.decimals(x))
+}
+
+// Format renders x rounded to p. With a fixed number of decimal places
+// trailing zeros are kept, so 0.5 at three significant figures is "0.500".
+// Values that round to below 1e-4 or to 1e6 and above are written in
+// exponent notation, as 1.11e-16 rather than sixteen zeros.
+func (p Precision) Format(x float64) string {
+	if p.Digits <= 0 || math.IsNaN(x) || math.IsInf(x, 0) {
+		return strconv.FormatFloat(x, 'g', -1, 64)
+	}
+	// Round first, so that 0.9996 at three significant figures is "1.00",
+	// with the places of 1, not "1.000" with those of 0.9996.
+	x = p.Round(x)
+	if a := math.Abs(x); a != 0 && (a < 1e-4 || a >= 1e6) {
+		if p.Significant {
+			return strconv.FormatFloat(x, 'e', p.Digits-1, 64)
+		}
+		return strconv.FormatFloat(x, 'e', p.Digits, 64)
+	}
+	d := p.decimals(x)
+	if d < 0 {
+		return strconv.FormatFloat(x, 'f', 0, 64)
+	}
+	return strconv.FormatFloat(x, 'f', d, 64)
+}
+
+// decimals returns the number of decimal places p keeps for x, which is
+// negative when significant figures end left of the decimal point.
+func (p Precision) decimals(x float64) int {
+	if !p.Significant {
+		return p.Digits
+	}
+	if x == 0 {
+		return p.Digits - 1
+	}
+	return p.Digits - 1 - int(math.Floor(math.Log10(math.Abs(x))))
+}
+
+// Round rounds x to the given number of decimal places, half away from zero.
+// Negative places round to tens, hundreds and so on. Where the scaling
+// overflows or underflows, as for 1.5e306 to three places or a subnormal to
+// its significant figures, x is returned unchanged.
+func Round(x float64, places int) float64 {
+	shift := math.Pow(10, float64(places))
+	scaled := x * shift
+	if shift == 0 || math.IsInf(shift, 0) || math.IsInf(scaled, 0) {
+		return x
+	}
+	return math.Round(scaled) / shift
+}
@@ -0,0 +1,76 @@
+package anscombe
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRound(t *testing.T) {
+	// Test case 1: Positive number
+	if rounded := Round(3.14159, 2); math.Abs(rounded-3.14) > 1e-6 {
+		t.Errorf("Round() returned an incorrect rounded value for positive number: expected 3.14, got %f", rounded)
+	}
+
+	// Test case 2: Negative number
+	if rounded := Round(-3.14159, 2); math.Abs(rounded+3.14) > 1e-6 {
+		t.Errorf("Round() returned an incorrect rounded value for negative number: expected -3.14, got %f", rounded)
+	}
+
+	// Test case 3: Negative places
+	if rounded := Round(1234.5, -2); rounded != 1200 {
+		t.Errorf("Round() returned an incorrect rounded value for -2 places: expected 1200, got %f", rounded)
+	}
+
+	// Test case 4: scaling that overflows or underflows leaves x unchanged
+	if rounded := Round(1.5e306, 3); rounded != 1.5e306 {
+		t.Errorf("Round() of a huge value: expected 1.5e306, got %g", rounded)
+	}
+	if rounded := Round(5e-320, 322); rounded != 5e-320 {
+		t.Errorf("Round() of a subnormal value: expected 5e-320, got %g", rounded)
+	}
+}
+
+func TestPrecision(t *testing.T) {
+	sig3 := Precision{Digits: 3, Significant: true}
+	dec2 := Precision{Digits: 2}
+	cases := []struct {
+		p    Precision
+		x    float64
+		want string
+	}{
+		{sig3, 0.5000909, "0.500"},
+		{sig3, 3.0000909, "3.00"},
+		{sig3, 0.8164205, "0.816"},
+		{sig3, 0.0021696, "0.00217"},
+		{sig3, 17.9899, "18.0"},
+		{sig3, 12345.6, "12300"},
+		{sig3, 0, "0.00"},
+		{dec2, 3.3166248, "3.32"},
+		{dec2, -0.004, "-0.00"},
+		{Precision{}, 3.3166247903554, "3.3166247903554"},
+		{sig3, math.NaN(), "NaN"},
+		{sig3, 0.99996, "1.00"},
+		{sig3, -0.0999996, "-0.100"},
+		{sig3, 1.1102230246251565e-16, "1.11e-16"},
+		{sig3, 3.2599999999999997e30, "3.26e+30"},
+		{sig3, 4934851.7, "4.93e+06"},
+		{sig3, 999999.7, "1.00e+06"},
+		{sig3, 0.00012345, "0.000123"},
+		{dec2, 2.5e-7, "0.00"},
+		{dec2, 1234567.891, "1.23e+06"},
+		{Precision{Digits: 3}, 1.5e306, "1.500e+306"},
+		{sig3, 5e-320, "5.00e-320"},
+		{sig3, -1.5e308, "-1.50e+308"},
+	}
+	for _, c := range cases {
+		if got := c.p.Format(c.x); got != c.want {
+			t.Errorf("%+v.Format(%g) = %q, expected %q", c.p, c.x, got, c.want)
+		}
+	}
+	if got := sig3.Round(0.0021696); math.Abs(got-0.00217) > 1e-15 {
+		t.Errorf("Round(0.0021696) at 3 significant figures = %g, expected 0.00217", got)
+	}
+	if got := (Precision{}).Round(math.Pi); got != math.Pi {
+		t.Errorf("Full precision changed the value: %g", got)
+	}
+}
@@ -0,0 +1,389 @@
+package anscombe
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/montanaflynn/stats"
+)
+
+// CheckDataQuality reports whether x and y can be analyzed together: both must
+// be non-empty, of the same length and free of NaN and infinite values. Every
+// problem is reported, as Errors of *ValueError.
+func CheckDataQuality(x, y []float64) error {
+	return Dataset{X: x, Y: y}.Validate()
+}
+
+// Validate is CheckDataQuality of the x and y of d, with the problems
+// recording the name of d. It applies the Lengths and NonFinite rules, which
+// every analysis needs whatever Options.Rules holds.
+func (d Dataset) Validate() error {
+	return Check(d, []Rule{Lengths{}, NonFinite{}}).Err()
+}
+
+// Severity ranks the findings of data-quality rules.
+type Severity int
+
+const (
+	// Info notes something worth knowing that does not affect the analysis.
+	Info Severity = iota + 1
+	// Warning marks data that can be analyzed but whose statistics may
+	// mislead.
+	Warning
+	// Critical marks data that cannot be analyzed. Summarize fails on it.
+	Critical
+)
+
+var severityNames = []string{Info: "info", Warning: "warning", Critical: "critical"}
+
+// String returns the name of s, e.g. "warning".
+func (s Severity) String() string {
+	if s < Info || s > Critical {
+		return "Severity(" + strconv.Itoa(int(s)) + ")"
+	}
+	return severityNames[s]
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s Severity) MarshalText() ([]byte, error) {
+	if s < Info || s > Critical {
+		return nil, fmt.Errorf("invalid severity %d", int(s))
+	}
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *Severity) UnmarshalText(b []byte) error {
+	for i, name := range severityNames {
+		if name != "" && name == string(b) {
+			*s = Severity(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown severity %q", b)
+}
+
+// or returns s, or def if s is zero.
+func (s Severity) or(def Severity) Severity {
+	if s == 0 {
+		return def
+	}
+	return s
+}
+
+// Finding is one problem a Rule found in a dataset.
+type Finding struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Variable string   `json:"variable,omitempty"` // "x" or "y", empty if the finding concerns both
+	Index    int      `json:"index"`              // position of the observation concerned, -1 if none
+	Value    Float    `json:"value"`              // the value at Index, null if none
+	Message  string   `json:"message"`
+	err      *ValueError
+}
+
+// Findings are the findings of the rules checked against one dataset, rule by
+// rule.
+type Findings []Finding
+
+// Err returns the critical findings as Errors of *ValueError, each wrapping
+// the sentinel of its rule, or nil if there are none.
+func (fs Findings) Err() error {
+	var errs Errors
+	for _, f := range fs {
+		if f.Severity >= Critical {
+			errs = append(errs, f.err)
+		}
+	}
+	return errs.err()
+}
+
+// Rule is a data-quality check. Check returns its findings about d, in the
+// order of the observations.
+type Rule interface {
+	Name() string
+	Check(d Dataset) []Finding
+}
+
+// DefaultRules returns every rule with its default thresholds and severities:
+// mismatched lengths, NaN and infinite values, fewer than two points and a
+// constant x are critical; too few points for inference, a constant y,
+// extreme values and x nearly collinear with the intercept are warnings; and
+// repeated observations are noted.
+func DefaultRules() []Rule {
+	return []Rule{Lengths{}, NonFinite{}, TooFew{}, Constant{}, Duplicates{}, Extreme{}, Collinear{}}
+}
+
+// Check applies rules to d and returns their findings.
+func Check(d Dataset, rules []Rule) Findings {
+	var findings Findings
+	for _, r := range rules {
+		findings = append(findings, r.Check(d)...)
+	}
+	return findings
+}
+
+// finding returns the finding of rule about the value v at index i of
+// variable, or about the whole variable if i is -1. Its error wraps sentinel;
+// msg is the message of the error if empty.
+func finding(d Dataset, rule string, sev Severity, variable string, i int, v float64, sentinel error, msg string) Finding {
+	if i < 0 {
+		v = math.NaN()
+	}
+	err := &ValueError{Set: d.Name, Variable: variable, Index: i, Value: v, Err: sentinel}
+	if msg == "" {
+		msg = err.Error()
+	}
+	return Finding{Rule: rule, Severity: sev, Variable: variable, Index: i, Value: Float(v), Message: msg, err: err}
+}
+
+// variables pairs the name of each variable of d with its values.
+func variables(d Dataset) []struct {
+	name   string
+	values []float64
+} {
+	return []struct {
+		name   string
+		values []float64
+	}{{"x", d.X}, {"y", d.Y}}
+}
+
+// pairs returns the number of complete (x, y) observations of d.
+func pairs(d Dataset) int {
+	return min(len(d.X), len(d.Y))
+}
+
+// Lengths finds empty variables and variables of different lengths. Its
+// findings are always critical.
+type Lengths struct{}
+
+// Name returns "lengths".
+func (Lengths) Name() string { return "lengths" }
+
+// Check implements Rule.
+func (r Lengths) Check(d Dataset) []Finding {
+	if len(d.X) == 0 && len(d.Y) == 0 {
+		return []Finding{finding(d, r.Name(), Critical, "", -1, 0, ErrEmptyInput, "")}
+	}
+	var findings []Finding
+	for _, v := range variables(d) {
+		if len(v.values) == 0 {
+			findings = append(findings, finding(d, r.Name(), Critical, v.name, -1, 0, ErrEmptyInput, ""))
+		}
+	}
+	if len(findings) == 0 && len(d.X) != len(d.Y) {
+		findings = append(findings, finding(d, r.Name(), Critical, "", -1, 0, ErrSize,
+			fmt.Sprintf("%d values of x but %d of y: %v", len(d.X), len(d.Y), ErrSize)))
+	}
+	return findings
+}
+
+// NonFinite finds NaN and infinite values. Its findings are always critical,
+// since no statistic is defined with them.
+type NonFinite struct{}
+
+// Name returns "non_finite".
+func (NonFinite) Name() string { return "non_finite" }
+
+// Check implements Rule.
+func (r NonFinite) Check(d Dataset) []Finding {
+	var findings []Finding
+	for _, v := range variables(d) {
+		for i, val := range v.values {
+			switch {
+			case math.IsNaN(val):
+				findings = append(findings, finding(d, r.Name(), Critical, v.name, i, val, ErrNaN, ""))
+			case math.IsInf(val, 0):
+				findings = append(findings, finding(d, r.Name(), Critical, v.name, i, val, ErrInfValue, ""))
+			}
+		}
+	}
+	return findings
+}
+
+// TooFew finds datasets with fewer than Min observations, too few for
+// standard errors, tests and intervals. Fewer than two observations, through
+// which no line can be drawn, are always critical.
+type TooFew struct {
+	Min      int      // fewest observations for inference, 3 if zero
+	Severity Severity // of a dataset with at least two but fewer than Min observations, Warning if zero
+}
+
+// Name returns "too_few".
+func (TooFew) Name() string { return "too_few" }
+
+// Check implements Rule.
+func (r TooFew) Check(d Dataset) []Finding {
+	least := r.Min
+	if least == 0 {
+		least = 3
+	}
+	switch n := pairs(d); {
+	case n == 0 || len(d.X) != len(d.Y):
+		return nil // reported by Lengths
+	case n < 2:
+		return []Finding{finding(d, r.Name(), Critical, "", -1, 0, ErrTooFew, "1 observation: a line needs at least 2")}
+	case n < least:
+		return []Finding{finding(d, r.Name(), r.Severity.or(Warning), "", -1, 0, ErrTooFew,
+			fmt.Sprintf("%d observations: inference needs at least %d", n, least))}
+	}
+	return nil
+}
+
+// Constant finds variables whose values are all equal. A constant x is always
+// critical, since no line can be fitted; a constant y can be fitted, with a
+// zero slope, but leaves R-squared and the correlation undefined.
+type Constant struct {
+	Severity Severity // of a constant y, Warning if zero
+}
+
+// Name returns "constant".
+func (Constant) Name() string { return "constant" }
+
+// Check implements Rule.
+func (r Constant) Check(d Dataset) []Finding {
+	var findings []Finding
+	for _, v := range variables(d) {
+		if len(v.values) < 2 || !finite(v.values) || !allEqual(v.values) {
+			continue
+		}
+		if v.name == "x" {
+			findings = append(findings, finding(d, r.Name(), Critical, v.name, -1, 0, ErrConstant,
+				fmt.Sprintf("x is constant at %g: no line can be fitted", v.values[0])))
+			continue
+		}
+		findings = append(findings, finding(d, r.Name(), r.Severity.or(Warning), v.name, -1, 0, ErrConstant,
+			fmt.Sprintf("y is constant at %g: R-squared and the correlation are undefined", v.values[0])))
+	}
+	return findings
+}
+
+// Duplicates finds observations that repeat an earlier (x, y) pair.
+type Duplicates struct {
+	Severity Severity // Info if zero
+}
+
+// Name returns "duplicates".
+func (Duplicates) Name() string { return "duplicates" }
+
+// Check implements Rule.
+func (r Duplicates) Check(d Dataset) []Finding {
+	var findings []Finding
+	first := make(map[[2]float64]int)
+	for i := 0; i < pairs(d); i++ {
+		p := [2]float64{d.X[i], d.Y[i]}
+		j, seen := first[p]
+		if !seen {
+			first[p] = i
+			continue
+		}
+		findings = append(findings, finding(d, r.Name(), r.Severity.or(Info), "", i, math.NaN(), ErrDuplicatePoint,
+			fmt.Sprintf("point %d (%g, %g) repeats point %d", i+1, p[0], p[1], j+1)))
+	}
+	return findings
+}
+
+// Extreme finds values far from the median of their variable by the modified
+// z-score of Iglewicz and Hoaglin, 0.6745 (v - median) / MAD. If more than
+// half the values are equal, so that the MAD is zero, the mean absolute
+// deviation from the median, times 1.2533, takes its place.
+type Extreme struct {
+	Threshold float64  // largest modified z-score that is not extreme, 3.5 if zero
+	Severity  Severity // Warning if zero
+}
+
+// Name returns "extreme".
+func (Extreme) Name() string { return "extreme" }
+
+// Check implements Rule.
+func (r Extreme) Check(d Dataset) []Finding {
+	threshold := r.Threshold
+	if threshold == 0 {
+		threshold = 3.5
+	}
+	var findings []Finding
+	for _, v := range variables(d) {
+		if len(v.values) < 3 || !finite(v.values) {
+			continue
+		}
+		median, _ := stats.Median(v.values)
+		scale := medianAbsoluteDeviation(v.values) / 0.6745
+		if scale == 0 {
+			var sum float64
+			for _, val := range v.values {
+				sum += math.Abs(val - median)
+			}
+			scale = 1.2533 * sum / float64(len(v.values))
+		}
+		if scale == 0 {
+			continue // constant, reported by Constant
+		}
+		for i, val := range v.values {
+			if z := (val - median) / scale; math.Abs(z) > threshold {
+				findings = append(findings, finding(d, r.Name(), r.Severity.or(Warning), v.name, i, val, ErrExtreme,
+					fmt.Sprintf("%s of point %d is %g, %.3g robust standard deviations from the median %g", v.name, i+1, val, z, median)))
+			}
+		}
+	}
+	return findings
+}
+
+// Collinear finds an x so far from zero relative to its spread that it is
+// nearly collinear with the intercept, which costs the fit digits. It
+// measures the condition number of the design matrix [1 x] with its columns
+// scaled to unit length, sqrt((1 + c) / (1 - c)) where c = |mean| / rms of x;
+// Belsley, Kuh and Welsch take 30 as the start of harmful collinearity.
+type Collinear struct {
+	MaxCondition float64  // largest condition number that is not collinear, 30 if zero
+	Severity     Severity // Warning if zero
+}
+
+// Name returns "collinear".
+func (Collinear) Name() string { return "collinear" }
+
+// Check implements Rule.
+func (r Collinear) Check(d Dataset) []Finding {
+	limit := r.MaxCondition
+	if limit == 0 {
+		limit = 30
+	}
+	if len(d.X) < 2 || !finite(d.X) {
+		return nil
+	}
+	var sum, sumSq float64
+	for _, v := range d.X {
+		sum += v
+		sumSq += v * v
+	}
+	n := float64(len(d.X))
+	c := math.Abs(sum) / math.Sqrt(n*sumSq)
+	if c >= 1 {
+		return nil // constant, reported by Constant
+	}
+	if cond := math.Sqrt((1 + c) / (1 - c)); cond > limit {
+		return []Finding{finding(d, r.Name(), r.Severity.or(Warning), "x", -1, 0, ErrCollinear,
+			fmt.Sprintf("x is nearly collinear with the intercept: condition number %.3g, above %g; centering x would help", cond, limit))}
+	}
+	return nil
+}
+
+// finite reports whether x holds neither NaN nor infinite values.
+func finite(x []float64) bool {
+	for _, v := range x {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// allEqual reports whether every value of x equals the first.
+func allEqual(x []float64) bool {
+	for _, v := range x {
+		if v != x[0] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,202 @@
+package anscombe
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestCheckDataQuality(t *testing.T) {
+	// Test case 1: Valid data
+	x := []float64{1, 2, 3, 4, 5}
+	y := []float64{2, 4, 6, 8, 10}
+	if err := CheckDataQuality(x, y); err != nil {
+		t.Errorf("CheckDataQuality() returned an error for valid data: %v", err)
+	}
+
+	// Test case 2: Empty data
+	if err := CheckDataQuality([]float64{}, []float64{}); !errors.Is(err, ErrEmptyInput) {
+		t.Errorf("CheckDataQuality() for empty data: expected %v, got %v", ErrEmptyInput, err)
+	}
+
+	// Test case 3: Mismatched lengths
+	if err := CheckDataQuality(x, y[:4]); !errors.Is(err, ErrSize) {
+		t.Errorf("CheckDataQuality() for mismatched data: expected %v, got %v", ErrSize, err)
+	}
+
+	// Test case 4: NaN value, with its variable, index and value
+	err := CheckDataQuality(x, []float64{2, 4, math.NaN(), 8, 10})
+	var ve *ValueError
+	if !errors.Is(err, ErrNaN) || !errors.As(err, &ve) || ve.Variable != "y" || ve.Index != 2 || !math.IsNaN(ve.Value) {
+		t.Errorf("CheckDataQuality() for NaN data: expected %v at y[2], got %v", ErrNaN, err)
+	}
+}
+
+func TestValidateReportsEverything(t *testing.T) {
+	// Test case 1: every problem is reported, in order, naming the set
+	d := Dataset{Name: "bad", X: []float64{1, math.Inf(1), 3}, Y: []float64{math.NaN(), 2}}
+	err := d.Validate()
+	var errs Errors
+	if !errors.As(err, &errs) || len(errs) != 3 {
+		t.Fatalf("Validate() = %v, expected three problems", err)
+	}
+	for i, want := range []error{ErrSize, ErrInfValue, ErrNaN} {
+		if !errors.Is(errs[i], want) {
+			t.Errorf("Problem %d = %v, expected %v", i, errs[i], want)
+		}
+		if ve, ok := errs[i].(*ValueError); !ok || ve.Set != "bad" {
+			t.Errorf("Problem %d = %#v, expected a *ValueError of set bad", i, errs[i])
+		}
+	}
+	if got := err.Error(); got != "Must be the same length.; x[1] = +Inf: Value is infinite.; y[0] = NaN: Not a number." {
+		t.Errorf("Validate().Error() = %q", got)
+	}
+
+	// Test case 2: long lists are cut short
+	many := Errors{ErrNaN, ErrNaN, ErrNaN, ErrNaN, ErrNaN, ErrNaN, ErrNaN}
+	if got := many.Error(); !strings.HasSuffix(got, "; and 2 more") {
+		t.Errorf("Errors.Error() = %q, expected the last two to be counted", got)
+	}
+
+	// Test case 3: valid data
+	if err := Quartet()[0].Validate(); err != nil {
+		t.Errorf("Validate() of set 1 returned an error: %v", err)
+	}
+}
+
+func TestMakeSeries(t *testing.T) {
+	series := MakeSeries([]float64{1, 2, 3}, []float64{2, 4, 6})
+	if len(series) != 3 || series[2].X != 3 || series[2].Y != 6 {
+		t.Errorf("MakeSeries() returned an incorrect series: %v", series)
+	}
+	if series := MakeSeries([]float64{1, 2}, []float64{1}); series != nil {
+		t.Errorf("MakeSeries() did not return nil for mismatched data: %v", series)
+	}
+}
+
+func TestDefaultRules(t *testing.T) {
+	// Test case 1: the outliers of sets 3 and 4 are the only findings in the quartet
+	want := map[string]string{"Set 3": "y 2", "Set 4": "x 7"}
+	for _, d := range Quartet() {
+		findings := Check(d, DefaultRules())
+		got := ""
+		for _, f := range findings {
+			if f.Rule != "extreme" || f.Severity != Warning {
+				t.Errorf("%s: unexpected finding %+v", d.Name, f)
+			}
+			got += fmt.Sprintf("%s %d", f.Variable, f.Index)
+		}
+		if got != want[d.Name] {
+			t.Errorf("%s: extreme values at %q, expected %q", d.Name, got, want[d.Name])
+		}
+		if err := findings.Err(); err != nil {
+			t.Errorf("%s: Err() = %v, expected nil for warnings", d.Name, err)
+		}
+	}
+
+	// Test case 2: a constant x is critical, a constant y a warning
+	flat := Check(Dataset{Name: "flat", X: []float64{2, 2, 2}, Y: []float64{1, 2, 4}}, DefaultRules())
+	if len(flat) != 1 || flat[0].Severity != Critical || flat[0].Variable != "x" {
+		t.Fatalf("Check() of a constant x = %+v", flat)
+	}
+	if level := Check(Dataset{X: []float64{1, 2, 3}, Y: []float64{5, 5, 5}}, DefaultRules()); len(level) != 1 || level[0].Severity != Warning || level[0].Variable != "y" {
+		t.Errorf("Check() of a constant y = %+v", level)
+	}
+	var ve *ValueError
+	if err := flat.Err(); !errors.Is(err, ErrConstant) || !errors.As(err, &ve) || ve.Set != "flat" || ve.Variable != "x" {
+		t.Errorf("Err() of a flat set = %v, expected the constant x", err)
+	}
+
+	// Test case 3: too few points, a repeated point and an x far from zero
+	findings := Check(Dataset{X: []float64{1e6, 1e6 + 1}, Y: []float64{1, 2}}, DefaultRules())
+	if len(findings) != 2 || findings[0].Rule != "too_few" || findings[1].Rule != "collinear" {
+		t.Errorf("Check() of two points far from zero = %+v", findings)
+	}
+	one := Check(Dataset{X: []float64{1}, Y: []float64{2}}, DefaultRules())
+	if err := one.Err(); !errors.Is(err, ErrTooFew) || err.Error() != "Too few points." {
+		t.Errorf("Err() of one point = %v, expected %v", err, ErrTooFew)
+	}
+	findings = Check(Dataset{X: []float64{1, 2, 1, 3}, Y: []float64{4, 5, 4, 7}}, DefaultRules())
+	if len(findings) != 1 || findings[0].Severity != Info || findings[0].Index != 2 || findings[0].Message != "point 3 (1, 4) repeats point 1" {
+		t.Errorf("Check() of a repeated point = %+v", findings)
+	}
+	repeated := Check(Dataset{X: []float64{1, 2, 1}, Y: []float64{4, 5, 4}}, []Rule{Duplicates{Severity: Critical}})
+	if err := repeated.Err(); !errors.Is(err, ErrDuplicatePoint) || err.Error() != "(x, y)[2]: Point is repeated." {
+		t.Errorf("Err() of a critical repeated point = %q", err)
+	}
+}
+
+func TestRuleConfiguration(t *testing.T) {
+	d := Quartet()[2]
+	// Test case 1: a lower threshold flags more values and a higher severity fails the set
+	loose := Check(d, []Rule{Extreme{Threshold: 1, Severity: Critical}})
+	if len(loose) < 2 {
+		t.Errorf("Extreme{Threshold: 1} found %d values, expected more than one", len(loose))
+	}
+	if _, err := Summarize(d, Options{Rules: []Rule{Extreme{Severity: Critical}}}); !errors.Is(err, ErrExtreme) {
+		t.Errorf("Summarize() with critical extreme values: expected %v, got %v", ErrExtreme, err)
+	}
+
+	// Test case 2: without rules only the checks of Validate apply
+	s, err := Summarize(d, Options{})
+	if err != nil || len(s.Findings) != 0 {
+		t.Errorf("Summarize() without rules = %v, %v", s.Findings, err)
+	}
+	if _, err := Summarize(Dataset{X: []float64{1, 2}, Y: []float64{1, math.NaN()}}, Options{}); !errors.Is(err, ErrNaN) {
+		t.Errorf("Summarize() of NaN without rules: expected %v, got %v", ErrNaN, err)
+	}
+
+	// Test case 3: TooFew with a higher minimum
+	if f := Check(d, []Rule{TooFew{Min: 20}}); len(f) != 1 || f[0].Message != "11 observations: inference needs at least 20" {
+		t.Errorf("TooFew{Min: 20} = %+v", f)
+	}
+}
+
+func TestConstantYAnalyzed(t *testing.T) {
+	// A constant y is only a warning, so the set is analyzed: the flat line
+	// and descriptive statistics are reported, and nothing is left undefined
+	// but R-squared and the correlation.
+	d := Dataset{Name: "flat", X: []float64{1, 2, 3, 4, 5, 6}, Y: []float64{3, 3, 3, 3, 3, 3}}
+	var buf bytes.Buffer
+	if err := WriteResults(&buf, Analyze([]Dataset{d}, DefaultOptions())); err != nil {
+		t.Fatalf("WriteResults() returned an error: %v", err)
+	}
+	var doc struct {
+		Sets []map[string]any `json:"sets"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("Results document is not valid JSON: %v", err)
+	}
+	s := doc.Sets[0]
+	if s["error"] != nil || s["r_squared"] != nil || s["correlation"] != nil || s["bootstrap"] != nil || s["mean_y"] != 3.0 {
+		t.Errorf("Results of a constant y: %v", s)
+	}
+	findings, _ := s["findings"].([]any)
+	if len(findings) != 1 || findings[0].(map[string]any)["severity"] != "warning" {
+		t.Errorf("Findings of a constant y: %v", s["findings"])
+	}
+	if inf := s["inference"].(map[string]any); inf["level"] != 0.0 {
+		t.Errorf("Inference of a constant y: %v", inf)
+	}
+}
+
+func TestSeverityText(t *testing.T) {
+	for _, s := range []Severity{Info, Warning, Critical} {
+		b, err := s.MarshalText()
+		var back Severity
+		if err != nil || back.UnmarshalText(b) != nil || back != s {
+			t.Errorf("Severity %v did not round trip: %s, %v", s, b, err)
+		}
+	}
+	if _, err := Severity(0).MarshalText(); err == nil {
+		t.Error("MarshalText() of the zero severity: expected an error")
+	}
+	var s Severity
+	if err := s.UnmarshalText([]byte("fatal")); err == nil {
+		t.Error("UnmarshalText(fatal): expected an error")
+	}
+}
@@ -0,0 +1,64 @@
+package anscombe
+
+import "fmt"
+
+// Registry is an ordered collection of uniquely named datasets. Iterating over
+// it always yields the datasets in the order they were added, so reports and
+// plot file names are the same from run to run.
+type Registry struct {
+	datasets []Dataset
+	index    map[string]int
+}
+
+// NewRegistry returns a registry holding datasets, in order. It fails if two
+// of them share a name.
+func NewRegistry(datasets ...Dataset) (*Registry, error) {
+	r := &Registry{index: make(map[string]int, len(datasets))}
+	for _, d := range datasets {
+		if err := r.Add(d); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Add appends d to the registry.
+func (r *Registry) Add(d Dataset) error {
+	if r.index == nil {
+		r.index = make(map[string]int)
+	}
+	if _, ok := r.index[d.Name]; ok {
+		return fmt.Errorf("%w: %q", ErrDuplicate, d.Name)
+	}
+	r.index[d.Name] = len(r.datasets)
+	r.datasets = append(r.datasets, d)
+	return nil
+}
+
+// Get returns the dataset called name.
+func (r *Registry) Get(name string) (Dataset, bool) {
+	i, ok := r.index[name]
+	if !ok {
+		return Dataset{}, false
+	}
+	return r.datasets[i], true
+}
+
+// Len returns the number of datasets in the registry.
+func (r *Registry) Len() int {
+	return len(r.datasets)
+}
+
+// Names returns the dataset names in registration order.
+func (r *Registry) Names() []string {
+	names := make([]string, len(r.datasets))
+	for i, d := range r.datasets {
+		names[i] = d.Name
+	}
+	return names
+}
+
+// Datasets returns the datasets in registration order.
+func (r *Registry) Datasets() []Dataset {
+	return append([]Dataset(nil), r.datasets...)
+}
@@ -0,0 +1,54 @@
+package anscombe
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestRegistryOrder(t *testing.T) {
+	r, err := NewRegistry(Quartet()...)
+	if err != nil {
+		t.Fatalf("NewRegistry() returned an error: %v", err)
+	}
+	want := []string{"Set 1", "Set 2", "Set 3", "Set 4"}
+	// Order must not depend on map iteration, so check it repeatedly.
+	for i := 0; i < 20; i++ {
+		if got := r.Names(); !reflect.DeepEqual(got, want) {
+			t.Fatalf("Names() returned %v, expected %v", got, want)
+		}
+	}
+	if err := r.Add(Dataset{Name: "dino"}); err != nil {
+		t.Fatalf("Add() returned an error: %v", err)
+	}
+	if d := r.Datasets(); r.Len() != 5 || d[4].Name != "dino" {
+		t.Errorf("Add() did not append the dataset: %v", r.Names())
+	}
+	if d, ok := r.Get("Set 3"); !ok || d.Y[2] != 12.74 {
+		t.Errorf("Get(\"Set 3\") returned %+v, %v", d, ok)
+	}
+	if _, ok := r.Get("Set 5"); ok {
+		t.Error("Get() found a dataset that was never added")
+	}
+}
+
+func TestRegistryDuplicate(t *testing.T) {
+	_, err := NewRegistry(Dataset{Name: "a"}, Dataset{Name: "b"}, Dataset{Name: "a"})
+	if !errors.Is(err, ErrDuplicate) {
+		t.Errorf("NewRegistry() with a duplicate name: expected %v, got %v", ErrDuplicate, err)
+	}
+}
+
+func TestSlug(t *testing.T) {
+	for name, want := range map[string]string{
+		"Set 1":          "set_1",
+		"IV":             "iv",
+		"  slant_down  ": "slant_down",
+		"x/y (raw)":      "x_y_raw",
+		"":               "dataset",
+	} {
+		if got := (Dataset{Name: name}).Slug(); got != want {
+			t.Errorf("Slug(%q) = %q, expected %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package anscombe
+
+import "math"
+
+// Fit is the ordinary least squares line y = Intercept + Slope*x fitted to a
+// dataset, together with the quantities derived from it.
+type Fit struct {
+	Intercept float64   `json:"intercept"`
+	Slope     float64   `json:"slope"`
+	Fitted    []float64 `json:"fitted"`    // fitted values, one per observation
+	Residuals []float64 `json:"residuals"` // observed minus fitted values
+	Leverage  []float64 `json:"leverage"`  // hat values, the diagonal of the hat matrix
+	SSE       float64   `json:"sse"`       // residual sum of squares
+	SST       float64   `json:"sst"`       // total sum of squares of y about its mean
+	DF        int       `json:"df"`        // residual degrees of freedom, n-2
+	MeanX     float64   `json:"mean_x"`    // mean of x
+	Sxx       float64   `json:"sxx"`       // sum of squared deviations of x about MeanX
+}
+
+// N returns the number of observations the line was fitted to, DF + 2.
+func (f Fit) N() int {
+	return f.DF + 2
+}
+
+// Predict returns the fitted value at x.
+func (f Fit) Predict(x float64) float64 {
+	return f.Intercept + f.Slope*x
+}
+
+// RSquared returns the coefficient of determination, 1 - SSE/SST.
+func (f Fit) RSquared() float64 {
+	return 1 - f.SSE/f.SST
+}
+
+// LinearRegression fits a least squares line to x and y using the closed-form
+// solution slope = Sxy/Sxx, intercept = mean(y) - slope*mean(x).
+func LinearRegression(x, y []float64) (Fit, error) {
+	if err := CheckDataQuality(x, y); err != nil {
+		return Fit{}, err
+	}
+	if len(x) < 2 {
+		return Fit{}, ErrTooFew
+	}
+	meanX, _ := Mean(x)
+	meanY, _ := Mean(y)
+	var sxx, sxy, sst float64
+	for i := range x {
+		dx, dy := x[i]-meanX, y[i]-meanY
+		sxx += dx * dx
+		sxy += dx * dy
+		sst += dy * dy
+	}
+	if sxx == 0 {
+		return Fit{}, ErrBounds
+	}
+	fit := Fit{
+		Slope:     sxy / sxx,
+		Fitted:    make([]float64, len(x)),
+		Residuals: make([]float64, len(x)),
+		Leverage:  make([]float64, len(x)),
+		SST:       sst,
+		DF:        len(x) - 2,
+		MeanX:     meanX,
+		Sxx:       sxx,
+	}
+	fit.Intercept = meanY - fit.Slope*meanX
+	for i := range x {
+		fit.Fitted[i] = fit.Predict(x[i])
+		fit.Residuals[i] = y[i] - fit.Fitted[i]
+		fit.SSE += fit.Residuals[i] * fit.Residuals[i]
+		dx := x[i] - meanX
+		fit.Leverage[i] = 1/float64(len(x)) + dx*dx/sxx
+	}
+	return fit, nil
+}
+
+// StandardizedResiduals returns the residuals divided by their estimated
+// standard deviations, s*sqrt(1-h), where s is the residual standard error and
+// h the leverage. A point with leverage one has an undefined standardized
+// residual, returned as NaN.
+func (f Fit) StandardizedResiduals() []float64 {
+	r := make([]float64, len(f.Residuals))
+	s := math.Sqrt(f.SSE / float64(f.DF))
+	for i, e := range f.Residuals {
+		if f.Leverage[i] >= 1-leverageTolerance {
+			r[i] = math.NaN()
+			continue
+		}
+		r[i] = e / (s * math.Sqrt(1-f.Leverage[i]))
+	}
+	return r
+}
+
+// leverageTolerance is how close to one a hat value may be before the point
+// is treated as fitted exactly, as x = 19 is in set 4 of the quartet.
+const leverageTolerance = 1e-9
@@ -0,0 +1,117 @@
+package anscombe
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLinearRegression(t *testing.T) {
+	// Test case 1: Valid data
+	x := []float64{1, 2, 3, 4, 5}
+	y := []float64{2, 3, 4, 5, 6}
+	fit, err := LinearRegression(x, y)
+	if err != nil {
+		t.Fatalf("LinearRegression() returned an error for valid data: %v", err)
+	}
+	if math.Abs(fit.Slope-1.0) > 1e-6 {
+		t.Errorf("Expected slope 1.0, got %f", fit.Slope)
+	}
+	if math.Abs(fit.Intercept-1.0) > 1e-6 {
+		t.Errorf("Expected intercept 1.0, got %f", fit.Intercept)
+	}
+
+	// Test case 2: Empty data
+	if _, err := LinearRegression([]float64{}, []float64{}); err == nil {
+		t.Error("LinearRegression() did not return an error for empty data")
+	}
+
+	// Test case 3: Constant x
+	if _, err := LinearRegression([]float64{2, 2, 2}, []float64{1, 2, 3}); err == nil {
+		t.Error("LinearRegression() did not return an error for constant x")
+	}
+}
+
+func TestLinearRegressionResiduals(t *testing.T) {
+	x := []float64{1, 2, 3, 4}
+	y := []float64{1, 3, 2, 4}
+	fit, err := LinearRegression(x, y)
+	if err != nil {
+		t.Fatalf("LinearRegression() returned an error for valid data: %v", err)
+	}
+	// slope = Sxy/Sxx = 4/5, intercept = 2.5 - 0.8*2.5
+	if math.Abs(fit.Slope-0.8) > 1e-12 || math.Abs(fit.Intercept-0.5) > 1e-12 {
+		t.Errorf("Expected slope 0.8 and intercept 0.5, got %f and %f", fit.Slope, fit.Intercept)
+	}
+	if fit.N() != 4 || fit.DF != 2 {
+		t.Errorf("Expected 4 observations and 2 degrees of freedom, got %d and %d", fit.N(), fit.DF)
+	}
+	var sum, sse float64
+	for i := range x {
+		if math.Abs(fit.Fitted[i]+fit.Residuals[i]-y[i]) > 1e-12 {
+			t.Errorf("Fitted value plus residual does not equal y at %d", i)
+		}
+		sum += fit.Residuals[i]
+		sse += fit.Residuals[i] * fit.Residuals[i]
+	}
+	if math.Abs(sum) > 1e-12 {
+		t.Errorf("Residuals do not sum to zero: %g", sum)
+	}
+	if math.Abs(fit.SSE-sse) > 1e-12 || math.Abs(fit.SSE-1.8) > 1e-12 {
+		t.Errorf("Expected SSE 1.8, got %f", fit.SSE)
+	}
+	if math.Abs(fit.SST-5) > 1e-12 {
+		t.Errorf("Expected SST 5.0, got %f", fit.SST)
+	}
+}
+
+func TestRSquared(t *testing.T) {
+	// Test case 1: Perfect fit
+	fit, err := LinearRegression([]float64{1, 2, 3, 4, 5}, []float64{2, 4, 6, 8, 10})
+	if err != nil {
+		t.Fatalf("LinearRegression() returned an error for valid data: %v", err)
+	}
+	if math.Abs(fit.RSquared()-1.0) > 1e-6 {
+		t.Errorf("RSquared() returned an incorrect R-squared value: expected 1.0, got %f", fit.RSquared())
+	}
+
+	// Test case 2: Partial fit
+	fit, err = LinearRegression([]float64{1, 2, 3, 4}, []float64{1, 3, 2, 4})
+	if err != nil {
+		t.Fatalf("LinearRegression() returned an error for valid data: %v", err)
+	}
+	if math.Abs(fit.RSquared()-0.64) > 1e-12 {
+		t.Errorf("RSquared() returned an incorrect R-squared value: expected 0.64, got %f", fit.RSquared())
+	}
+}
+
+func TestLeverage(t *testing.T) {
+	quartet := Quartet()
+	fit, err := LinearRegression(quartet[3].X, quartet[3].Y)
+	if err != nil {
+		t.Fatalf("LinearRegression() returned an error: %v", err)
+	}
+	// The hat values sum to the number of coefficients.
+	var sum float64
+	for _, h := range fit.Leverage {
+		sum += h
+	}
+	if math.Abs(sum-2) > 1e-12 {
+		t.Errorf("Leverage sums to %g, expected 2", sum)
+	}
+	// Set 4's point at x = 19 determines the slope on its own.
+	if math.Abs(fit.Leverage[7]-1) > 1e-12 || math.Abs(fit.Leverage[0]-0.1) > 1e-12 {
+		t.Errorf("Expected leverage 1 at x = 19 and 0.1 elsewhere, got %v", fit.Leverage)
+	}
+	r := fit.StandardizedResiduals()
+	if !math.IsNaN(r[7]) {
+		t.Errorf("Expected an undefined standardized residual at x = 19, got %g", r[7])
+	}
+
+	// Set 3's outlier at x = 13 has the largest standardized residual.
+	fit, _ = LinearRegression(quartet[2].X, quartet[2].Y)
+	r = fit.StandardizedResiduals()
+	s := math.Sqrt(fit.SSE / float64(fit.DF))
+	if want := fit.Residuals[2] / (s * math.Sqrt(1-fit.Leverage[2])); math.Abs(r[2]-want) > 1e-12 || r[2] < 2.5 {
+		t.Errorf("Expected a standardized residual of %g at x = 13, got %g", want, r[2])
+	}
+}
@@ -0,0 +1,179 @@
+package anscombe
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// Format names an output format of the report layer.
+type Format string
+
+const (
+	Text Format = "text" // human-readable report, as in results.txt
+	JSON Format = "json" // the Results document
+)
+
+// DefaultPrecision returns the precision each format uses unless configured
+// otherwise: three significant figures for text, matching Anscombe's table,
+// and full precision for JSON.
+func DefaultPrecision(f Format) Precision {
+	if f == Text {
+		return Precision{Digits: 3, Significant: true}
+	}
+	return Precision{}
+}
+
+// WriteReport writes r to w in format f with numbers rounded to p.
+func WriteReport(w io.Writer, r Results, f Format, p Precision) error {
+	switch f {
+	case Text:
+		return writeText(w, r, p)
+	case JSON:
+		if p.Digits > 0 {
+			var err error
+			if r, err = Rounded(r, p); err != nil {
+				return err
+			}
+		}
+		return WriteResults(w, r)
+	}
+	return fmt.Errorf("unknown report format %q", f)
+}
+
+func writeText(w io.Writer, r Results, p Precision) error {
+	bw := bufio.NewWriter(w)
+	f := p.Format
+	for i, s := range r.Sets {
+		if i > 0 {
+			fmt.Fprintln(bw)
+		}
+		fmt.Fprintf(bw, "%s\n", s.Name)
+		fmt.Fprintf(bw, "  Observations: %d\n", s.N)
+		if m := s.Missing; m != nil {
+			fmt.Fprintf(bw, "  Missing x or y: %d, handled by %s: %d dropped, %d imputed\n", m.Missing, m.Policy, m.Dropped, m.Imputed)
+		}
+		for _, fd := range s.Findings {
+			fmt.Fprintf(bw, "  Data quality %s (%s): %s\n", fd.Severity, fd.Rule, fd.Message)
+		}
+		if s.Error != "" {
+			fmt.Fprintf(bw, "  Error: %s\n", s.Error)
+			continue
+		}
+		fmt.Fprintf(bw, "  Mean X: %s, Mean Y: %s\n", f(s.MeanX), f(s.MeanY))
+		fmt.Fprintf(bw, "  Variance X: %s, Variance Y: %s [%v]\n", f(s.VarianceX), f(s.VarianceY), s.DDOF)
+		fmt.Fprintf(bw, "  Std. Dev. X: %s, Std. Dev. Y: %s [%v]\n", f(s.StdDevX), f(s.StdDevY), s.DDOF)
+		for _, v := range []struct {
+			name string
+			d    Description
+		}{{"X", s.DescriptionX}, {"Y", s.DescriptionY}} {
+			fmt.Fprintf(bw, "  %s: median %s, quartiles [%s, %s], IQR %s, range [%s, %s], MAD %s\n", v.name,
+				f(v.d.Median), f(v.d.Q1), f(v.d.Q3), f(v.d.IQR), f(v.d.Min), f(v.d.Max), f(v.d.MAD))
+			fmt.Fprintf(bw, "  %s: skewness %s, excess kurtosis %s, CV %s [%v]\n", v.name,
+				f(float64(v.d.Skewness)), f(float64(v.d.ExcessKurtosis)), f(float64(v.d.CV)), v.d.DDOF)
+		}
+		fmt.Fprintf(bw, "  Correlation: %s\n", f(float64(s.Correlation)))
+		for _, a := range s.Associations {
+			switch {
+			case a.Error != "":
+				fmt.Fprintf(bw, "  %s: Error: %s\n", a.Measure, a.Error)
+			case math.IsNaN(float64(a.P)):
+				fmt.Fprintf(bw, "  %s: %s\n", a.Measure, f(a.Value))
+			default:
+				fmt.Fprintf(bw, "  %s: %s (permutation p %s)\n", a.Measure, f(a.Value), f(float64(a.P)))
+			}
+		}
+		fmt.Fprintf(bw, "  R-squared: %s\n", f(float64(s.RSquared)))
+		if inf := s.Inference; inf.Level == 0 {
+			fmt.Fprintf(bw, "  Intercept: %s, Slope: %s\n", f(s.Fit.Intercept), f(s.Fit.Slope))
+		} else {
+			for _, c := range []struct {
+				name string
+				c    Coefficient
+			}{{"Intercept", inf.Intercept}, {"Slope", inf.Slope}} {
+				fmt.Fprintf(bw, "  %s: %s (std. error %s, t %s, p %s, %g%% CI [%s, %s])\n", c.name,
+					f(c.c.Estimate), f(c.c.StdErr), f(float64(c.c.T)), f(float64(c.c.P)), inf.Level*100, f(c.c.Lower), f(c.c.Upper))
+			}
+			fmt.Fprintf(bw, "  Residual std. error: %s on %d degrees of freedom\n", f(inf.ResidualStdErr), s.Fit.DF)
+			fmt.Fprintf(bw, "  F-statistic: %s on 1 and %d DF, p %s\n", f(float64(inf.F)), s.Fit.DF, f(float64(inf.FP)))
+		}
+		if b := s.Bootstrap; b != nil {
+			fmt.Fprintf(bw, "  Bootstrap: %d %s resamples (%d failed), seed %d\n", b.Replicates, b.Method, b.Failed, b.Seed)
+			for _, in := range b.Intervals {
+				fmt.Fprintf(bw, "  Bootstrap %s: std. error %s, %g%% percentile CI [%s, %s], BCa [%s, %s]\n", in.Statistic, f(float64(in.StdErr)), b.Level*100,
+					f(float64(in.Percentile[0])), f(float64(in.Percentile[1])), f(float64(in.BCa[0])), f(float64(in.BCa[1])))
+			}
+		}
+		for _, r := range s.Robust {
+			if r.Error != "" {
+				fmt.Fprintf(bw, "  %s fit: Error: %s\n", r.Estimator, r.Error)
+				continue
+			}
+			fmt.Fprintf(bw, "  %s fit: Intercept: %s, Slope: %s, shift from OLS: intercept %s, slope %s, largest gap %s\n",
+				r.Estimator, f(r.Intercept), f(r.Slope), f(r.InterceptShift), f(r.SlopeShift), f(r.MaxGap))
+		}
+		if pf := s.Polynomial; pf != nil && pf.Error != "" {
+			fmt.Fprintf(bw, "  Degree %d polynomial: Error: %s\n", pf.Degree, pf.Error)
+		} else if pf != nil {
+			coefficients := make([]string, len(pf.Coefficients))
+			for k, c := range pf.Coefficients {
+				coefficients[k] = f(c)
+			}
+			fmt.Fprintf(bw, "  Degree %d polynomial: coefficients [%s] (constant first), R-squared %s, adjusted R-squared %s\n",
+				pf.Degree, strings.Join(coefficients, ", "), f(float64(pf.RSquared)), f(float64(pf.AdjustedRSquared)))
+			fmt.Fprintf(bw, "  Degree %d polynomial vs. line: F %s on %d and %d DF, p %s\n", pf.Degree, f(float64(pf.F)), pf.Degree-1, pf.DF, f(float64(pf.FP)))
+		}
+		for _, in := range s.Flagged() {
+			fmt.Fprintf(bw, "  Influential point %d (x %s, y %s): %s\n", in.Index+1, f(in.X), f(in.Y), strings.Join(in.Flags, ", "))
+		}
+	}
+	if failed := r.Failed(); len(failed) > 0 {
+		fmt.Fprintf(bw, "\nFailed sets: %d of %d\n", len(failed), len(r.Sets))
+		for _, e := range failed {
+			fmt.Fprintf(bw, "  %v\n", e)
+		}
+	}
+	return bw.Flush()
+}
+
+// Rounded returns a deep copy of v with every number rounded to p. The copy
+// is made by a JSON round trip, so v must decode from its own encoding, as
+// Results and the types it is built from do.
+func Rounded[T any](v T, p Precision) (T, error) {
+	var buf bytes.Buffer
+	var c T
+	if err := json.NewEncoder(&buf).Encode(v); err != nil {
+		return c, err
+	}
+	if err := json.NewDecoder(&buf).Decode(&c); err != nil {
+		return c, err
+	}
+	roundFloats(reflect.ValueOf(&c).Elem(), p)
+	return c, nil
+}
+
+func roundFloats(v reflect.Value, p Precision) {
+	switch v.Kind() {
+	case reflect.Float64:
+		v.SetFloat(p.Round(v.Float()))
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				roundFloats(v.Field(i), p)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			roundFloats(v.Index(i), p)
+		}
+	case reflect.Pointer:
+		if !v.IsNil() {
+			roundFloats(v.Elem(), p)
+		}
+	}
+}
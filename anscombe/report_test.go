@@ -0,0 +1,73 @@
+package anscombe
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteReportText(t *testing.T) {
+	var buf bytes.Buffer
+	results := Analyze(append(Quartet()[:1], Quartet()[3], Dataset{Name: "empty"}), DefaultOptions())
+	if err := WriteReport(&buf, results, Text, DefaultPrecision(Text)); err != nil {
+		t.Fatalf("WriteReport() returned an error: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"Set 1\n",
+		"Variance X: 11.0, Variance Y: 4.13 [sample (n-1)]",
+		"X: median 9.00, quartiles [6.50, 11.5], IQR 5.00, range [4.00, 14.0], MAD 3.00\n",
+		"Y: skewness -0.0558, excess kurtosis -0.821, CV 0.271 [sample (n-1)]\n",
+		"Correlation: 0.816",
+		"Spearman's rho: 0.818 (permutation p 0.00400)\n",
+		"Kendall's tau-b: 0.426 (permutation p 0.162)\n",
+		"Intercept: 3.00 (std. error 1.12, t 2.67, p 0.0257, 95% CI [0.456, 5.54])",
+		"Slope: 0.500 (std. error 0.118,",
+		"F-statistic: 18.0 on 1 and 9 DF, p 0.00217",
+		"Theil-Sen fit: Intercept: 2.94, Slope: 0.502, shift from OLS: intercept -0.0634, slope 0.00158, largest gap 0.0571\n",
+		"Degree 2 polynomial: coefficients [0.755, 1.07, -0.0316] (constant first), R-squared 0.687, adjusted R-squared 0.609\n",
+		"Degree 2 polynomial vs. line: F 0.532 on 1 and 8 DF, p 0.487\n",
+		"Degree 2 polynomial: Error: Input is outside of range.\n",
+		"Bootstrap: 1999 pairs resamples (0 failed), seed 0\n",
+		"Bootstrap Slope: std. error ",
+		"BCa [NaN, NaN]\n",
+		"Influential point 8 (x 19.0, y 12.5): leverage\n",
+		"empty\n  Observations: 0\n  Data quality critical (lengths): Input must not be empty.\n  Error: Input must not be empty.",
+		"Failed sets: 1 of 3\n  empty: Input must not be empty.\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Text report is missing %q:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	if err := WriteReport(&buf, results, Text, Precision{Digits: 2}); err != nil {
+		t.Fatalf("WriteReport() returned an error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "Std. Dev. X: 3.32") {
+		t.Errorf("Text report did not use two decimal places:\n%s", buf.String())
+	}
+}
+
+func TestWriteReportJSONPrecision(t *testing.T) {
+	results := Analyze(Quartet(), DefaultOptions())
+	var buf bytes.Buffer
+	if err := WriteReport(&buf, results, JSON, Precision{Digits: 3, Significant: true}); err != nil {
+		t.Fatalf("WriteReport() returned an error: %v", err)
+	}
+	rounded, err := ReadResults(&buf)
+	if err != nil {
+		t.Fatalf("ReadResults() returned an error: %v", err)
+	}
+	if s := rounded.Sets[0]; s.StdDevX != 3.32 || s.Fit.Slope != 0.5 || s.Fit.Residuals[0] != 0.039 {
+		t.Errorf("JSON values were not rounded to 3 significant figures: %v %v %v", s.StdDevX, s.Fit.Slope, s.Fit.Residuals[0])
+	}
+	// Rounding for output must not touch the computed values.
+	if results.Sets[0].StdDevX == 3.32 || results.Sets[0].Fit.Residuals[0] == 0.039 {
+		t.Error("WriteReport() rounded the caller's results in place")
+	}
+
+	if err := WriteReport(&buf, results, Format("xml"), Precision{}); err == nil {
+		t.Error("WriteReport() accepted an unknown format")
+	}
+}
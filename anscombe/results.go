@@ -0,0 +1,73 @@
+package anscombe
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// Results is the machine-readable document written alongside results.txt. It
+// carries every value of the text report so that downstream tools do not
+// have to parse it.
+type Results struct {
+	Sets []SetResult `json:"sets"`
+}
+
+// SetResult is the analysis of one dataset. If the dataset could not be
+// analyzed, only Name and Error are set.
+type SetResult struct {
+	Summary
+	Error string `json:"error,omitempty"` // data-quality or analysis failure
+	err   error
+}
+
+// Analyze summarizes every dataset with opts, in order, recording failures in
+// the result of the set they occurred in instead of stopping.
+func Analyze(datasets []Dataset, opts Options) Results {
+	results := Results{Sets: make([]SetResult, 0, len(datasets))}
+	for _, d := range datasets {
+		s, err := Summarize(d, opts)
+		r := SetResult{Summary: s}
+		if err != nil {
+			d, missing := opts.Missing.Apply(d)
+			r = SetResult{Summary: Summary{Name: d.Name, N: d.Len(), DDOF: opts.DDOF, Findings: Check(d, opts.Rules)}, Error: err.Error(), err: err}
+			if missing.Missing > 0 {
+				r.Missing = &missing
+			}
+		}
+		results.Sets = append(results.Sets, r)
+	}
+	return results
+}
+
+// Failed returns the sets that could not be analyzed, in order, each as a
+// SetError wrapping the reason. The reasons of results decoded by ReadResults
+// are only their messages.
+func (r Results) Failed() []*SetError {
+	var failed []*SetError
+	for _, s := range r.Sets {
+		if s.Error == "" {
+			continue
+		}
+		err := s.err
+		if err == nil {
+			err = errors.New(s.Error)
+		}
+		failed = append(failed, &SetError{Set: s.Name, Err: err})
+	}
+	return failed
+}
+
+// WriteResults writes r to w as indented JSON.
+func WriteResults(w io.Writer, r Results) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// ReadResults decodes a document written by WriteResults.
+func ReadResults(rd io.Reader) (Results, error) {
+	var r Results
+	err := json.NewDecoder(rd).Decode(&r)
+	return r, err
+}
@@ -0,0 +1,83 @@
+package anscombe
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestAnalyzeRecordsFailures(t *testing.T) {
+	datasets := append(Quartet(), Dataset{Name: "short", X: []float64{1, 2}, Y: []float64{1}})
+	results := Analyze(datasets, DefaultOptions())
+	if len(results.Sets) != 5 {
+		t.Fatalf("Expected 5 results, got %d", len(results.Sets))
+	}
+	for _, r := range results.Sets[:4] {
+		if r.Error != "" {
+			t.Errorf("%s: unexpected error %q", r.Name, r.Error)
+		}
+	}
+	if r := results.Sets[4]; r.Name != "short" || r.Error != ErrSize.Error() {
+		t.Errorf("Expected the short set to fail with %q, got %+v", ErrSize, r)
+	}
+
+	failed := results.Failed()
+	if len(failed) != 1 || failed[0].Set != "short" || !errors.Is(failed[0], ErrSize) {
+		t.Errorf("Failed() = %v, expected the short set wrapping ErrSize", failed)
+	}
+}
+
+func TestResultsDocument(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteResults(&buf, Analyze(Quartet(), DefaultOptions())); err != nil {
+		t.Fatalf("WriteResults() returned an error: %v", err)
+	}
+
+	// Downstream tools see flat, snake_case keys.
+	var doc struct {
+		Sets []map[string]any `json:"sets"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("Results document is not valid JSON: %v", err)
+	}
+	for _, key := range []string{"name", "n", "mean_x", "variance_y", "correlation", "r_squared", "fit", "inference"} {
+		if _, ok := doc.Sets[0][key]; !ok {
+			t.Errorf("Results document is missing key %q", key)
+		}
+	}
+
+	results, err := ReadResults(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("ReadResults() returned an error: %v", err)
+	}
+	s := results.Sets[2]
+	if s.Name != "Set 3" || math.Abs(s.Inference.Slope.StdErr-0.118) > 0.001 || len(s.Fit.Residuals) != 11 {
+		t.Errorf("ReadResults() returned unexpected values for set 3: %+v", s)
+	}
+}
+
+func TestResultsUndefinedStatistics(t *testing.T) {
+	// A perfect fit has infinite t and F statistics and a constant y an
+	// undefined R-squared and correlation; all are written as null.
+	datasets := []Dataset{
+		{Name: "perfect", X: []float64{1, 2, 3, 4, 5, 6}, Y: []float64{2, 4, 6, 8, 10, 12}},
+		{Name: "constant", X: []float64{1, 2, 3, 4, 5, 6}, Y: []float64{3, 3, 3, 3, 3, 3}},
+	}
+	var buf bytes.Buffer
+	if err := WriteResults(&buf, Analyze(datasets, DefaultOptions())); err != nil {
+		t.Fatalf("WriteResults() returned an error: %v", err)
+	}
+	results, err := ReadResults(&buf)
+	if err != nil {
+		t.Fatalf("ReadResults() returned an error: %v", err)
+	}
+	perfect, constant := results.Sets[0], results.Sets[1]
+	if perfect.Error != "" || perfect.RSquared != 1 || !math.IsNaN(float64(perfect.Inference.F)) {
+		t.Errorf("Perfect fit: R-squared %v, F %v, error %q", perfect.RSquared, perfect.Inference.F, perfect.Error)
+	}
+	if constant.Error != "" || !math.IsNaN(float64(constant.RSquared)) || !math.IsNaN(float64(constant.Correlation)) {
+		t.Errorf("Constant y: R-squared %v, correlation %v, error %q", constant.RSquared, constant.Correlation, constant.Error)
+	}
+}
@@ -0,0 +1,354 @@
+package anscombe
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/montanaflynn/stats"
+)
+
+// Line is a straight line y = Intercept + Slope*x.
+type Line struct {
+	Intercept float64 `json:"intercept"`
+	Slope     float64 `json:"slope"`
+}
+
+// Predict returns the value of the line at x.
+func (l Line) Predict(x float64) float64 {
+	return l.Intercept + l.Slope*x
+}
+
+// Estimator fits a straight line to paired observations. OLS, TheilSen,
+// MEstimator and RANSAC implement it.
+type Estimator interface {
+	Name() string
+	Estimate(x, y []float64) (Line, error)
+}
+
+// DefaultEstimators returns the robust estimators compared with the least
+// squares line by DefaultOptions.
+func DefaultEstimators() []Estimator {
+	return []Estimator{TheilSen{}, MEstimator{Loss: Huber}, MEstimator{Loss: Bisquare}, RANSAC{}}
+}
+
+// OLS is the ordinary least squares estimator of LinearRegression.
+type OLS struct{}
+
+// Name returns "OLS".
+func (OLS) Name() string { return "OLS" }
+
+// Estimate fits the least squares line.
+func (OLS) Estimate(x, y []float64) (Line, error) {
+	fit, err := LinearRegression(x, y)
+	if err != nil {
+		return Line{}, err
+	}
+	return Line{Intercept: fit.Intercept, Slope: fit.Slope}, nil
+}
+
+// TheilSen is the Theil-Sen estimator: the slope is the median of the slopes
+// between every pair of points with distinct x, and the intercept the median
+// of y - slope*x. Up to 29% of the points can be arbitrarily bad before it
+// breaks down.
+type TheilSen struct{}
+
+// Name returns "Theil-Sen".
+func (TheilSen) Name() string { return "Theil-Sen" }
+
+// Estimate fits the Theil-Sen line. It returns ErrBounds if all x are equal.
+func (TheilSen) Estimate(x, y []float64) (Line, error) {
+	if err := checkLine(x, y); err != nil {
+		return Line{}, err
+	}
+	var slopes []float64
+	for i := range x {
+		for j := i + 1; j < len(x); j++ {
+			if x[i] != x[j] {
+				slopes = append(slopes, (y[j]-y[i])/(x[j]-x[i]))
+			}
+		}
+	}
+	if len(slopes) == 0 {
+		return Line{}, ErrBounds
+	}
+	slope, _ := stats.Median(slopes)
+	offsets := make([]float64, len(x))
+	for i := range x {
+		offsets[i] = y[i] - slope*x[i]
+	}
+	intercept, _ := stats.Median(offsets)
+	return Line{Intercept: intercept, Slope: slope}, nil
+}
+
+// Loss selects the weight function of an MEstimator.
+type Loss int
+
+const (
+	// Huber weights residuals beyond the tuning constant down in proportion
+	// to their size.
+	Huber Loss = iota
+	// Bisquare is Tukey's biweight, which gives residuals beyond the tuning
+	// constant no weight at all.
+	Bisquare
+)
+
+// MEstimator fits a line by iteratively reweighted least squares, starting
+// from the least squares line. Residuals are scaled by their median absolute
+// value divided by 0.6745, a consistent estimate of the standard deviation of
+// normal errors.
+type MEstimator struct {
+	Loss    Loss
+	Tuning  float64 // tuning constant, 1.345 for Huber and 4.685 for Bisquare if zero (95% efficiency under normal errors)
+	MaxIter int     // iterations before giving up with ErrConverge, 50 if zero
+	Tol     float64 // relative change of the coefficients that counts as converged, 1e-8 if zero
+}
+
+// Name returns "Huber" or "Tukey bisquare".
+func (m MEstimator) Name() string {
+	if m.Loss == Bisquare {
+		return "Tukey bisquare"
+	}
+	return "Huber"
+}
+
+// Estimate fits the M-estimate of the line. It returns ErrConverge if the
+// coefficients are still changing after MaxIter iterations.
+func (m MEstimator) Estimate(x, y []float64) (Line, error) {
+	line, err := OLS{}.Estimate(x, y)
+	if err != nil {
+		return Line{}, err
+	}
+	c, maxIter, tol := m.Tuning, m.MaxIter, m.Tol
+	if c == 0 {
+		c = 1.345
+		if m.Loss == Bisquare {
+			c = 4.685
+		}
+	}
+	if maxIter == 0 {
+		maxIter = 50
+	}
+	if tol == 0 {
+		tol = 1e-8
+	}
+
+	abs := make([]float64, len(x))
+	w := make([]float64, len(x))
+	for iter := 0; iter < maxIter; iter++ {
+		for i := range x {
+			abs[i] = math.Abs(y[i] - line.Predict(x[i]))
+		}
+		mad, _ := stats.Median(abs)
+		scale := mad / 0.6745
+		if scale == 0 {
+			// At least half the points lie on the line already.
+			return line, nil
+		}
+		for i := range x {
+			u := abs[i] / scale
+			switch {
+			case u <= c && m.Loss == Bisquare:
+				w[i] = (1 - (u/c)*(u/c)) * (1 - (u/c)*(u/c))
+			case u <= c:
+				w[i] = 1
+			case m.Loss == Bisquare:
+				w[i] = 0
+			default:
+				w[i] = c / u
+			}
+		}
+		next, err := weightedLeastSquares(x, y, w)
+		if err != nil {
+			return Line{}, err
+		}
+		converged := math.Abs(next.Intercept-line.Intercept) <= tol*(1+math.Abs(line.Intercept)) &&
+			math.Abs(next.Slope-line.Slope) <= tol*(1+math.Abs(line.Slope))
+		line = next
+		if converged {
+			return line, nil
+		}
+	}
+	return Line{}, ErrConverge
+}
+
+// weightedLeastSquares fits the line minimizing the weighted sum of squared
+// residuals. It returns ErrBounds if the points with weight have a single x.
+func weightedLeastSquares(x, y, w []float64) (Line, error) {
+	var sw, sx, sy float64
+	for i := range x {
+		sw += w[i]
+		sx += w[i] * x[i]
+		sy += w[i] * y[i]
+	}
+	if sw == 0 {
+		return Line{}, ErrBounds
+	}
+	meanX, meanY := sx/sw, sy/sw
+	var sxx, sxy float64
+	for i := range x {
+		dx := x[i] - meanX
+		sxx += w[i] * dx * dx
+		sxy += w[i] * dx * (y[i] - meanY)
+	}
+	if sxx == 0 {
+		return Line{}, ErrBounds
+	}
+	slope := sxy / sxx
+	return Line{Intercept: meanY - slope*meanX, Slope: slope}, nil
+}
+
+// RANSAC fits a line by random sample consensus: it draws pairs of points,
+// counts the points within Threshold of the line through each pair, and fits
+// least squares to the largest such consensus set. Ties go to the set with
+// the smaller residual sum of squares about its pair's line. When more than
+// half of y are equal, so that their median absolute deviation is zero, the
+// default threshold is the residual standard error of the least squares line
+// instead, so that inliers need not lie exactly on a line.
+type RANSAC struct {
+	Threshold  float64 // largest absolute residual of an inlier, the median absolute deviation of y if zero
+	Iterations int     // pairs drawn, 1000 if zero; every pair is tried instead when there are no more than that
+	Seed       int64   // seed of the pair sampler, so results are reproducible; CompareRobust fills in a zero Seed
+}
+
+// Name returns "RANSAC".
+func (RANSAC) Name() string { return "RANSAC" }
+
+// Estimate fits the RANSAC line. It returns ErrBounds if all x are equal.
+func (r RANSAC) Estimate(x, y []float64) (Line, error) {
+	if err := checkLine(x, y); err != nil {
+		return Line{}, err
+	}
+	threshold := r.Threshold
+	if threshold == 0 {
+		threshold = medianAbsoluteDeviation(y)
+	}
+	if threshold == 0 {
+		if fit, err := LinearRegression(x, y); err == nil && fit.DF > 0 {
+			threshold = math.Sqrt(fit.SSE / float64(fit.DF))
+		}
+	}
+	iterations := r.Iterations
+	if iterations == 0 {
+		iterations = 1000
+	}
+
+	n := len(x)
+	var best []int
+	bestSSE := math.Inf(1)
+	try := func(i, j int) {
+		if x[i] == x[j] {
+			return
+		}
+		slope := (y[j] - y[i]) / (x[j] - x[i])
+		candidate := Line{Intercept: y[i] - slope*x[i], Slope: slope}
+		var inliers []int
+		var sse float64
+		for k := range x {
+			if e := y[k] - candidate.Predict(x[k]); math.Abs(e) <= threshold {
+				inliers = append(inliers, k)
+				sse += e * e
+			}
+		}
+		if len(inliers) > len(best) || len(inliers) == len(best) && sse < bestSSE {
+			best, bestSSE = inliers, sse
+		}
+	}
+	if n*(n-1)/2 <= iterations {
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				try(i, j)
+			}
+		}
+	} else {
+		rng := rand.New(rand.NewSource(r.Seed))
+		for k := 0; k < iterations; k++ {
+			i, j := rng.Intn(n), rng.Intn(n-1)
+			if j >= i {
+				j++
+			}
+			try(i, j)
+		}
+	}
+	if best == nil {
+		return Line{}, ErrBounds
+	}
+	bx, by := make([]float64, len(best)), make([]float64, len(best))
+	for k, i := range best {
+		bx[k], by[k] = x[i], y[i]
+	}
+	return OLS{}.Estimate(bx, by)
+}
+
+// medianAbsoluteDeviation returns the median of |x - median(x)|.
+func medianAbsoluteDeviation(x []float64) float64 {
+	m, _ := stats.Median(x)
+	dev := make([]float64, len(x))
+	for i, v := range x {
+		dev[i] = math.Abs(v - m)
+	}
+	mad, _ := stats.Median(dev)
+	return mad
+}
+
+// checkLine checks that x and y are usable data for a line: non-empty, of
+// equal length, free of NaN and at least two points long.
+func checkLine(x, y []float64) error {
+	if err := CheckDataQuality(x, y); err != nil {
+		return err
+	}
+	if len(x) < 2 {
+		return ErrTooFew
+	}
+	return nil
+}
+
+// RobustFit compares the line of a robust Estimator with the least squares
+// line fitted to the same dataset.
+type RobustFit struct {
+	Estimator      string  `json:"estimator"`
+	Intercept      float64 `json:"intercept"`
+	Slope          float64 `json:"slope"`
+	InterceptShift float64 `json:"intercept_shift"` // Intercept minus the least squares intercept
+	SlopeShift     float64 `json:"slope_shift"`     // Slope minus the least squares slope
+	MaxGap         float64 `json:"max_gap"`         // largest vertical distance between the two lines over the range of x
+	Error          string  `json:"error,omitempty"` // set instead of the other fields if the estimator failed
+}
+
+// seeded is an Estimator that samples at random, and takes seed unless it
+// has a seed of its own.
+type seeded interface {
+	withSeed(seed int64) Estimator
+}
+
+func (r RANSAC) withSeed(seed int64) Estimator {
+	if r.Seed == 0 {
+		r.Seed = seed
+	}
+	return r
+}
+
+// CompareRobust fits e to x and y and compares the result with fit, the least
+// squares line of the same data. An estimator that samples at random without
+// a seed of its own, such as RANSAC{}, is seeded with seed, Options.Seed in
+// Summarize. A failing estimator is reported in Error.
+func CompareRobust(e Estimator, fit Fit, x, y []float64, seed int64) RobustFit {
+	if s, ok := e.(seeded); ok {
+		e = s.withSeed(seed)
+	}
+	r := RobustFit{Estimator: e.Name()}
+	line, err := e.Estimate(x, y)
+	if err != nil {
+		r.Error = err.Error()
+		return r
+	}
+	r.Intercept, r.Slope = line.Intercept, line.Slope
+	r.InterceptShift = line.Intercept - fit.Intercept
+	r.SlopeShift = line.Slope - fit.Slope
+	// The gap is linear in x, so it is largest at one end of the range.
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range x {
+		lo, hi = math.Min(lo, v), math.Max(hi, v)
+	}
+	r.MaxGap = math.Max(math.Abs(r.InterceptShift+r.SlopeShift*lo), math.Abs(r.InterceptShift+r.SlopeShift*hi))
+	return r
+}
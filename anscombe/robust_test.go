@@ -0,0 +1,140 @@
+package anscombe
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTheilSen(t *testing.T) {
+	// Test case 1: one wild point does not move the line through the rest
+	x := []float64{1, 2, 3, 4, 5, 6, 7}
+	y := []float64{3, 5, 7, 9, 11, 13, 100}
+	line, err := TheilSen{}.Estimate(x, y)
+	if err != nil {
+		t.Fatalf("TheilSen.Estimate() returned an error: %v", err)
+	}
+	if line.Intercept != 1 || line.Slope != 2 {
+		t.Errorf("TheilSen.Estimate() = %+v, expected y = 1 + 2x", line)
+	}
+
+	// Test case 2: no pair of points has distinct x
+	if _, err := (TheilSen{}).Estimate([]float64{8, 8, 8}, []float64{1, 2, 3}); err != ErrBounds {
+		t.Errorf("TheilSen.Estimate() with a single x: expected %v, got %v", ErrBounds, err)
+	}
+
+	// Test case 3: a single point
+	if _, err := (TheilSen{}).Estimate([]float64{1}, []float64{1}); err != ErrTooFew {
+		t.Errorf("TheilSen.Estimate() with one point: expected %v, got %v", ErrTooFew, err)
+	}
+}
+
+// TestRobustSet3 checks that every default estimator recovers the line
+// through the ten points of set 3 that are not the outlier.
+func TestRobustSet3(t *testing.T) {
+	d := Quartet()[2]
+	x := append(append([]float64{}, d.X[:2]...), d.X[3:]...)
+	y := append(append([]float64{}, d.Y[:2]...), d.Y[3:]...)
+	clean, _ := LinearRegression(x, y)
+	fit, _ := LinearRegression(d.X, d.Y)
+
+	for _, e := range DefaultEstimators() {
+		r := CompareRobust(e, fit, d.X, d.Y, 0)
+		if r.Error != "" {
+			t.Errorf("%s returned an error: %s", e.Name(), r.Error)
+			continue
+		}
+		if math.Abs(r.Intercept-clean.Intercept) > 0.01 || math.Abs(r.Slope-clean.Slope) > 0.001 {
+			t.Errorf("%s: y = %g + %gx, expected about y = %g + %gx", e.Name(), r.Intercept, r.Slope, clean.Intercept, clean.Slope)
+		}
+		if math.Abs(r.SlopeShift-(r.Slope-fit.Slope)) > 1e-12 || r.SlopeShift > -0.15 {
+			t.Errorf("%s: slope shift %g from OLS slope %g", e.Name(), r.SlopeShift, fit.Slope)
+		}
+		// The lines cross inside the range, so the gap is largest at x = 14.
+		if want := math.Abs(r.InterceptShift + 14*r.SlopeShift); math.Abs(r.MaxGap-want) > 1e-12 {
+			t.Errorf("%s: max gap %g, expected %g", e.Name(), r.MaxGap, want)
+		}
+	}
+}
+
+func TestMEstimator(t *testing.T) {
+	d := Quartet()[0]
+	if name := (MEstimator{Loss: Bisquare}).Name(); name != "Tukey bisquare" {
+		t.Errorf("MEstimator.Name() = %q", name)
+	}
+
+	// Test case 1: exactly collinear data is its own fit
+	line, err := MEstimator{}.Estimate([]float64{1, 2, 3, 4}, []float64{2, 4, 6, 8})
+	if err != nil || math.Abs(line.Intercept) > 1e-12 || math.Abs(line.Slope-2) > 1e-12 {
+		t.Errorf("MEstimator.Estimate() of y = 2x = %+v, %v", line, err)
+	}
+
+	// Test case 2: too few iterations
+	if _, err := (MEstimator{MaxIter: 1}).Estimate(d.X, d.Y); err != ErrConverge {
+		t.Errorf("MEstimator.Estimate() with one iteration: expected %v, got %v", ErrConverge, err)
+	}
+	fit, _ := LinearRegression(d.X, d.Y)
+	if r := CompareRobust(MEstimator{MaxIter: 1}, fit, d.X, d.Y, 0); r.Error != ErrConverge.Error() || r.Estimator != "Huber" {
+		t.Errorf("CompareRobust() of a failing estimator = %+v", r)
+	}
+}
+
+func TestRANSACSampling(t *testing.T) {
+	// Test case 1: 60 points have more pairs than iterations, so pairs are
+	// drawn at random; a fifth of the points are far off y = 1 + 0.5x.
+	var x, y []float64
+	for i := 0; i < 60; i++ {
+		x = append(x, float64(i))
+		v := 1 + 0.5*float64(i) + 0.1*math.Sin(float64(i))
+		if i%5 == 0 {
+			v += 20
+		}
+		y = append(y, v)
+	}
+	r := RANSAC{Threshold: 1, Iterations: 200, Seed: 7}
+	first, err := r.Estimate(x, y)
+	if err != nil {
+		t.Fatalf("RANSAC.Estimate() returned an error: %v", err)
+	}
+	if math.Abs(first.Slope-0.5) > 0.01 || math.Abs(first.Intercept-1) > 0.1 {
+		t.Errorf("RANSAC.Estimate() = %+v, expected about y = 1 + 0.5x", first)
+	}
+	// Test case 2: the same seed gives the same line
+	if again, _ := r.Estimate(x, y); again != first {
+		t.Errorf("RANSAC.Estimate() with the same seed gave %+v, then %+v", first, again)
+	}
+
+	// Test case 3: no pair of points has distinct x
+	if _, err := (RANSAC{}).Estimate([]float64{8, 8}, []float64{1, 2}); err != ErrBounds {
+		t.Errorf("RANSAC.Estimate() with a single x: expected %v, got %v", ErrBounds, err)
+	}
+
+	// Test case 4: CompareRobust seeds a RANSAC without a seed of its own
+	fit, _ := LinearRegression(x, y)
+	if c := CompareRobust(RANSAC{Threshold: 1, Iterations: 200}, fit, x, y, 7); c.Slope != first.Slope || c.Intercept != first.Intercept {
+		t.Errorf("CompareRobust() with seed 7 = %+v, expected %+v", c, first)
+	}
+
+	// Test case 5: with most of y equal the MAD is zero, and the threshold
+	// falls back to the residual standard error, so near points are inliers
+	x = []float64{1, 2, 3, 4, 5, 6, 7, 8, 9}
+	y = []float64{2, 2, 2, 2, 2, 2.3, 1.8, 2.4, 9}
+	fit, _ = LinearRegression(x, y)
+	want, _ := RANSAC{Threshold: math.Sqrt(fit.SSE / float64(fit.DF))}.Estimate(x, y)
+	exact, _ := RANSAC{Threshold: 1e-12}.Estimate(x, y)
+	if got, err := (RANSAC{}).Estimate(x, y); err != nil || got != want || got == exact {
+		t.Errorf("RANSAC.Estimate() with a zero MAD = %+v, %v, expected %+v, not the exact fit %+v", got, err, want, exact)
+	}
+}
+
+func TestSummarizeRobust(t *testing.T) {
+	s, err := Summarize(Quartet()[0], DefaultOptions())
+	if err != nil {
+		t.Fatalf("Summarize() returned an error: %v", err)
+	}
+	if len(s.Robust) != len(DefaultEstimators()) || s.Robust[0].Estimator != "Theil-Sen" {
+		t.Errorf("Summarize() compared %+v", s.Robust)
+	}
+	if s, _ = Summarize(Quartet()[0], Options{}); s.Robust != nil {
+		t.Errorf("Summarize() without estimators compared %+v", s.Robust)
+	}
+}
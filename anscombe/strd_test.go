@@ -0,0 +1,257 @@
+package anscombe
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// strdCase is a linear regression dataset from the NIST Statistical Reference
+// Datasets, https://www.itl.nist.gov/div898/strd/lls/lls.shtml, together with
+// its certified results, as stored in testdata/strd.
+type strdCase struct {
+	Name       string `json:"name"`
+	Difficulty string `json:"difficulty"` // NIST's level of difficulty: lower, average or higher
+	Model      string `json:"model"`
+	Degree     int    `json:"degree"` // degree of the polynomial in x, or 0 if x holds one value per predictor
+	Intercept  bool   `json:"intercept"`
+	Certified  struct {
+		Coefficients []float64 `json:"coefficients"`
+		StdErrors    []float64 `json:"std_errors"`
+		ResidualSD   float64   `json:"residual_sd"`
+		RSquared     float64   `json:"r_squared"` // uncentered when the model has no intercept
+	} `json:"certified"`
+	Observations []struct {
+		Y float64   `json:"y"`
+		X []float64 `json:"x"`
+	} `json:"observations"`
+}
+
+// strdResult holds the quantities certified for each dataset, as computed by
+// one fit routine.
+type strdResult struct {
+	Coefficients []float64
+	StdErrors    []float64
+	ResidualSD   float64
+	RSquared     float64
+}
+
+// strdRoutine is a fit routine under test. Fit reports ok false for datasets
+// whose model the routine cannot express.
+type strdRoutine struct {
+	Name string
+	Fit  func(c strdCase) (r strdResult, ok bool, err error)
+}
+
+// strdDigits is the number of significant digits to which every routine must
+// reproduce the certified coefficients, standard errors, residual standard
+// deviation and R-squared of each dataset. Ill-conditioned designs leave fewer:
+// Filip's degree-10 polynomial in x between -9 and -3 keeps about seven, and
+// Wampler4 and Wampler5 multiply the conditioning of Wampler1's quintic by
+// residuals of order 1e5 and 1e7.
+var strdDigits = map[string]int{
+	"Norris":   11,
+	"Pontius":  11,
+	"NoInt1":   13,
+	"NoInt2":   13,
+	"Filip":    7,
+	"Longley":  10,
+	"Wampler1": 9,
+	"Wampler2": 11,
+	"Wampler3": 9,
+	"Wampler4": 7,
+	"Wampler5": 5,
+}
+
+// strdRoutineDigits lowers strdDigits for a routine whose method, rather than
+// a fault, limits it on one dataset, keyed by "routine/dataset". The one-pass
+// Comoments.Fit takes SSE as Syy less the explained sum of squares, which for
+// Norris, with R-squared 0.99999, cancels about five of the sixteen digits.
+var strdRoutineDigits = map[string]int{
+	"Comoments.Fit/Norris": 10,
+}
+
+var strdRoutines = []strdRoutine{
+	{"LinearRegression", func(c strdCase) (strdResult, bool, error) {
+		if c.Degree != 1 || !c.Intercept {
+			return strdResult{}, false, nil
+		}
+		x, y := c.xy()
+		fit, err := LinearRegression(x, y)
+		if err != nil {
+			return strdResult{}, true, err
+		}
+		r, err := lineResult(fit)
+		return r, true, err
+	}},
+	{"Comoments.Fit", func(c strdCase) (strdResult, bool, error) {
+		if c.Degree != 1 || !c.Intercept {
+			return strdResult{}, false, nil
+		}
+		x, y := c.xy()
+		var m Comoments
+		for i := range x {
+			m.Add(x[i], y[i])
+		}
+		fit, err := m.Fit()
+		if err != nil {
+			return strdResult{}, true, err
+		}
+		r, err := lineResult(fit)
+		return r, true, err
+	}},
+	{"FitBasis", func(c strdCase) (strdResult, bool, error) {
+		x, y, b := c.basis()
+		fit, err := FitBasis(x, y, b)
+		if err != nil {
+			return strdResult{}, true, err
+		}
+		return strdResult{
+			Coefficients: fit.Coefficients,
+			StdErrors:    fit.StdErrors(),
+			ResidualSD:   math.Sqrt(fit.SSE / float64(fit.DF)),
+			RSquared:     fit.RSquared(),
+		}, true, nil
+	}},
+}
+
+// lineResult takes the certified quantities of a straight line from its
+// inference statistics.
+func lineResult(fit Fit) (strdResult, error) {
+	inf, err := fit.Inference(0.95)
+	if err != nil {
+		return strdResult{}, err
+	}
+	return strdResult{
+		Coefficients: []float64{fit.Intercept, fit.Slope},
+		StdErrors:    []float64{inf.Intercept.StdErr, inf.Slope.StdErr},
+		ResidualSD:   inf.ResidualStdErr,
+		RSquared:     fit.RSquared(),
+	}, nil
+}
+
+// xy returns the single predictor and the response of a polynomial dataset.
+func (c strdCase) xy() (x, y []float64) {
+	for _, o := range c.Observations {
+		x = append(x, o.X[0])
+		y = append(y, o.Y)
+	}
+	return x, y
+}
+
+// basis returns the data and basis FitBasis needs for the model of c. A model
+// with several predictors, as Longley's, is fitted against the observation
+// index, each basis function looking up its own predictor.
+func (c strdCase) basis() (x, y []float64, b Basis) {
+	if c.Degree > 0 {
+		x, y = c.xy()
+		b = PolynomialBasis(c.Degree)
+		if !c.Intercept {
+			b = b[1:]
+		}
+		return x, y, b
+	}
+	if c.Intercept {
+		b = append(b, func(float64) float64 { return 1 })
+	}
+	for k := range c.Observations[0].X {
+		k := k
+		b = append(b, func(i float64) float64 { return c.Observations[int(i)].X[k] })
+	}
+	for i, o := range c.Observations {
+		x = append(x, float64(i))
+		y = append(y, o.Y)
+	}
+	return x, y, b
+}
+
+// logRelativeError returns the number of significant digits to which got
+// agrees with the certified value want, -log10(|got-want|/|want|), or the
+// number of correct decimal places, -log10|got|, when want is zero. Exact
+// agreement counts as 15 digits, all a float64 reliably carries.
+func logRelativeError(got, want float64) float64 {
+	if got == want {
+		return 15
+	}
+	lre := -math.Log10(math.Abs(got - want))
+	if want != 0 {
+		lre = -math.Log10(math.Abs(got-want) / math.Abs(want))
+	}
+	if math.IsNaN(lre) {
+		return 0
+	}
+	return math.Min(lre, 15)
+}
+
+func loadStRD(t *testing.T) []strdCase {
+	t.Helper()
+	paths, err := filepath.Glob("testdata/strd/*.json")
+	if err != nil || len(paths) == 0 {
+		t.Fatalf("no StRD fixtures in testdata/strd: %v", err)
+	}
+	var cases []strdCase
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", path, err)
+		}
+		var c strdCase
+		if err := json.Unmarshal(data, &c); err != nil {
+			t.Fatalf("Failed to parse %s: %v", path, err)
+		}
+		cases = append(cases, c)
+	}
+	return cases
+}
+
+// TestStRD checks every fit routine against the certified values of the NIST
+// StRD linear regression datasets, to the number of significant digits given
+// in strdDigits, and reports each routine and quantity that falls short.
+// Run with -v for the digits each routine achieves.
+func TestStRD(t *testing.T) {
+	for _, c := range loadStRD(t) {
+		digits, ok := strdDigits[c.Name]
+		if !ok {
+			t.Errorf("%s: no required digits in strdDigits", c.Name)
+			continue
+		}
+		for _, routine := range strdRoutines {
+			t.Run(c.Name+"/"+routine.Name, func(t *testing.T) {
+				digits := digits
+				if d, ok := strdRoutineDigits[routine.Name+"/"+c.Name]; ok {
+					digits = d
+				}
+				got, ok, err := routine.Fit(c)
+				if !ok {
+					t.Skipf("%s cannot fit %s", routine.Name, c.Model)
+				}
+				if err != nil {
+					t.Fatalf("%s failed on %s (%s difficulty): %v", routine.Name, c.Name, c.Difficulty, err)
+				}
+				worst := 15.0
+				check := func(quantity string, got, want float64) {
+					lre := logRelativeError(got, want)
+					worst = math.Min(worst, lre)
+					if lre < float64(digits) {
+						t.Errorf("%s on %s: %s is %.15g, certified %.15g, agreeing to %.1f significant digits; expected %d",
+							routine.Name, c.Name, quantity, got, want, lre, digits)
+					}
+				}
+				for k, want := range c.Certified.Coefficients {
+					check(fmt.Sprintf("B%d", k), got.Coefficients[k], want)
+				}
+				for k, want := range c.Certified.StdErrors {
+					check(fmt.Sprintf("standard error of B%d", k), got.StdErrors[k], want)
+				}
+				check("residual standard deviation", got.ResidualSD, c.Certified.ResidualSD)
+				if c.Intercept {
+					check("R-squared", got.RSquared, c.Certified.RSquared)
+				}
+				t.Logf("%s on %s: at least %.1f significant digits", routine.Name, c.Name, worst)
+			})
+		}
+	}
+}
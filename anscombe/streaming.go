@@ -0,0 +1,221 @@
+package anscombe
+
+import (
+	"fmt"
+	"io"
+	"math"
+)
+
+// KahanSum is a running sum with Neumaier's compensation, which carries the
+// low-order bits lost by each addition so that the error does not grow with
+// the number of terms. The zero value is an empty sum.
+type KahanSum struct {
+	sum, c float64
+}
+
+// Add adds x to the sum.
+func (k *KahanSum) Add(x float64) {
+	t := k.sum + x
+	if math.Abs(k.sum) >= math.Abs(x) {
+		k.c += (k.sum - t) + x
+	} else {
+		k.c += (x - t) + k.sum
+	}
+	k.sum = t
+}
+
+// Sum returns the compensated sum.
+func (k KahanSum) Sum() float64 {
+	return k.sum + k.c
+}
+
+// Moments accumulates the count, mean and sum of squared deviations of a
+// stream of values with Welford's algorithm, which never subtracts two large
+// sums and so keeps its precision when the mean is large compared with the
+// spread. The zero value is empty.
+type Moments struct {
+	n        int
+	mean, m2 KahanSum
+}
+
+// Add adds x to the accumulator.
+func (m *Moments) Add(x float64) {
+	delta := x - m.Mean()
+	m.n++
+	m.mean.Add(delta / float64(m.n))
+	m.m2.Add(delta * (x - m.Mean()))
+}
+
+// Merge adds the values accumulated by o, as if they had been added one by
+// one, using the pairwise update of Chan, Golub and LeVeque.
+func (m *Moments) Merge(o Moments) {
+	if o.n == 0 {
+		return
+	}
+	n := m.n + o.n
+	delta := o.Mean() - m.Mean()
+	m.mean.Add(delta * float64(o.n) / float64(n))
+	m.m2.Add(o.m2.Sum())
+	m.m2.Add(delta * delta * float64(m.n) * float64(o.n) / float64(n))
+	m.n = n
+}
+
+// N returns the number of values added.
+func (m Moments) N() int {
+	return m.n
+}
+
+// Mean returns the mean of the values added, zero if there are none.
+func (m Moments) Mean() float64 {
+	return m.mean.Sum()
+}
+
+// Variance returns the variance of the values added, as Variance does for a
+// slice.
+func (m Moments) Variance(ddof DDOF) (float64, error) {
+	if m.n == 0 {
+		return 0, ErrEmptyInput
+	}
+	if ddof < 0 || m.n <= int(ddof) {
+		return 0, ErrTooFew
+	}
+	return m.m2.Sum() / float64(m.n-int(ddof)), nil
+}
+
+// Comoments accumulates the moments of x and y and their co-moment, the sum
+// of products of deviations, for a stream of pairs. It holds everything a
+// least squares line needs, so a fit takes one pass over the data in
+// constant memory. The zero value is empty.
+type Comoments struct {
+	X, Y Moments
+	cxy  KahanSum
+}
+
+// Add adds the pair (x, y) to the accumulator.
+func (c *Comoments) Add(x, y float64) {
+	dx := x - c.X.Mean()
+	c.X.Add(x)
+	c.Y.Add(y)
+	c.cxy.Add(dx * (y - c.Y.Mean()))
+}
+
+// Merge adds the pairs accumulated by o, so chunks of a stream can be
+// accumulated separately, for instance in parallel, and combined.
+func (c *Comoments) Merge(o Comoments) {
+	if o.N() == 0 {
+		return
+	}
+	na, nb := float64(c.N()), float64(o.N())
+	dx, dy := o.X.Mean()-c.X.Mean(), o.Y.Mean()-c.Y.Mean()
+	c.X.Merge(o.X)
+	c.Y.Merge(o.Y)
+	c.cxy.Add(o.cxy.Sum())
+	c.cxy.Add(dx * dy * na * nb / (na + nb))
+}
+
+// N returns the number of pairs added.
+func (c Comoments) N() int {
+	return c.X.N()
+}
+
+// Covariance returns the covariance of x and y, dividing the co-moment by
+// n - ddof.
+func (c Comoments) Covariance(ddof DDOF) (float64, error) {
+	if c.N() == 0 {
+		return 0, ErrEmptyInput
+	}
+	if ddof < 0 || c.N() <= int(ddof) {
+		return 0, ErrTooFew
+	}
+	return c.cxy.Sum() / float64(c.N()-int(ddof)), nil
+}
+
+// Correlation returns Pearson's correlation of x and y, or ErrBounds if
+// either is constant.
+func (c Comoments) Correlation() (float64, error) {
+	if c.N() == 0 {
+		return 0, ErrEmptyInput
+	}
+	sxx, syy := c.X.m2.Sum(), c.Y.m2.Sum()
+	if sxx == 0 || syy == 0 {
+		return 0, ErrBounds
+	}
+	return c.cxy.Sum() / math.Sqrt(sxx*syy), nil
+}
+
+// Fit returns the least squares line through the pairs added. The result has
+// no per-observation Fitted, Residuals or Leverage, but its sums of squares,
+// DF, MeanX and Sxx support RSquared, Inference and the intervals as for a
+// Fit from LinearRegression. Since SSE is taken as Syy less the explained sum
+// of squares, a close fit loses about log10(1/(1-r^2)) significant digits of
+// it. It returns ErrTooFew for fewer than two pairs and ErrBounds if x is
+// constant.
+func (c Comoments) Fit() (Fit, error) {
+	if c.N() < 2 {
+		return Fit{}, ErrTooFew
+	}
+	sxx, syy, sxy := c.X.m2.Sum(), c.Y.m2.Sum(), c.cxy.Sum()
+	if sxx == 0 {
+		return Fit{}, ErrBounds
+	}
+	slope := sxy / sxx
+	return Fit{
+		Intercept: c.Y.Mean() - slope*c.X.Mean(),
+		Slope:     slope,
+		// Rounding can leave a perfect fit a tiny negative SSE.
+		SSE:   math.Max(syy-slope*sxy, 0),
+		SST:   syy,
+		DF:    c.N() - 2,
+		MeanX: c.X.Mean(),
+		Sxx:   sxx,
+	}, nil
+}
+
+// StreamedSet is the accumulated data of one set read by StreamCSV.
+type StreamedSet struct {
+	Name string
+	Comoments
+}
+
+// StreamCSV reads delimited text from r in one pass, as ReadCSV would, but
+// accumulates each set into Comoments instead of keeping its values, so that
+// files of any length can be fitted in constant memory per set. Sets are
+// returned in order of first appearance. A missing, NaN or infinite value,
+// which would poison the accumulated sums, is reported with its line as a
+// *ValueError giving its set, variable and index in the set.
+func StreamCSV(r io.Reader, opts CSVOptions) ([]StreamedSet, error) {
+	var sets []StreamedSet
+	index := map[string]int{}
+	err := scanCSV(r, opts, func(name string, x, y float64, line int) error {
+		i, ok := index[name]
+		if !ok {
+			i = len(sets)
+			index[name] = i
+			sets = append(sets, StreamedSet{Name: name})
+		}
+		for _, v := range []struct {
+			name  string
+			value float64
+		}{{"x", x}, {"y", y}} {
+			var sentinel error
+			switch {
+			case math.IsNaN(v.value):
+				sentinel = ErrNaN
+			case math.IsInf(v.value, 0):
+				sentinel = ErrInfValue
+			default:
+				continue
+			}
+			return fmt.Errorf("line %d: %w", line, &ValueError{Set: name, Variable: v.name, Index: sets[i].N(), Value: v.value, Err: sentinel})
+		}
+		sets[i].Add(x, y)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(sets) == 0 {
+		return nil, ErrEmptyInput
+	}
+	return sets, nil
+}
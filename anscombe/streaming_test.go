@@ -0,0 +1,178 @@
+package anscombe
+
+import (
+	"errors"
+	"math"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestKahanSum(t *testing.T) {
+	// Test case 1: the 1 is lost by naive summation
+	var k KahanSum
+	for _, v := range []float64{1e16, 1, -1e16} {
+		k.Add(v)
+	}
+	if k.Sum() != 1 {
+		t.Errorf("KahanSum of 1e16, 1, -1e16 = %g, expected 1", k.Sum())
+	}
+
+	// Test case 2: a million tenths
+	k = KahanSum{}
+	var naive float64
+	for i := 0; i < 1000000; i++ {
+		k.Add(0.1)
+		naive += 0.1
+	}
+	if math.Abs(k.Sum()-100000) > 1e-9 || math.Abs(naive-100000) < 1e-9 {
+		t.Errorf("KahanSum of a million tenths = %.12f, naive sum %.12f", k.Sum(), naive)
+	}
+}
+
+func TestMoments(t *testing.T) {
+	// Test case 1: a large offset defeats the one-pass sum of squares but
+	// not Welford's update
+	x := []float64{1e9 + 4, 1e9 + 7, 1e9 + 13, 1e9 + 16}
+	var m Moments
+	var sum, sumSq float64
+	for _, v := range x {
+		m.Add(v)
+		sum += v
+		sumSq += v * v
+	}
+	if v, err := m.Variance(Sample); err != nil || v != 30 {
+		t.Errorf("Moments.Variance() = %g, %v, expected 30", v, err)
+	}
+	if naive := (sumSq - sum*sum/4) / 3; math.Abs(naive-30) < 1 {
+		t.Errorf("The one-pass sum of squares gave %g, so the test data no longer shows the cancellation", naive)
+	}
+
+	// Test case 2: merged chunks agree with a single pass
+	d := Quartet()[0]
+	var whole, left, right Moments
+	for i, v := range d.Y {
+		whole.Add(v)
+		if i < 4 {
+			left.Add(v)
+		} else {
+			right.Add(v)
+		}
+	}
+	left.Merge(right)
+	want, _ := Variance(d.Y, Sample)
+	for _, m := range []Moments{whole, left} {
+		got, _ := m.Variance(Sample)
+		if m.N() != 11 || math.Abs(m.Mean()-7.500909) > 1e-6 || math.Abs(got-want) > 1e-12 {
+			t.Errorf("Moments: n %d, mean %g, variance %g, expected variance %g", m.N(), m.Mean(), got, want)
+		}
+	}
+
+	// Test case 3: too few values
+	if _, err := (Moments{}).Variance(Sample); err != ErrEmptyInput {
+		t.Errorf("Moments.Variance() when empty: expected %v, got %v", ErrEmptyInput, err)
+	}
+	var one Moments
+	one.Add(1)
+	if _, err := one.Variance(Sample); err != ErrTooFew {
+		t.Errorf("Moments.Variance() of one value: expected %v, got %v", ErrTooFew, err)
+	}
+}
+
+func TestComoments(t *testing.T) {
+	for _, d := range Quartet() {
+		want, _ := LinearRegression(d.X, d.Y)
+		// Accumulate in three chunks and merge them out of order.
+		var chunks [3]Comoments
+		for i := range d.X {
+			chunks[i%3].Add(d.X[i], d.Y[i])
+		}
+		c := chunks[2]
+		c.Merge(chunks[0])
+		c.Merge(chunks[1])
+		c.Merge(Comoments{})
+
+		got, err := c.Fit()
+		if err != nil {
+			t.Fatalf("Comoments.Fit(%s) returned an error: %v", d.Name, err)
+		}
+		for _, v := range []struct {
+			name      string
+			got, want float64
+		}{
+			{"intercept", got.Intercept, want.Intercept},
+			{"slope", got.Slope, want.Slope},
+			{"SSE", got.SSE, want.SSE},
+			{"SST", got.SST, want.SST},
+			{"Sxx", got.Sxx, want.Sxx},
+		} {
+			if math.Abs(v.got-v.want) > 1e-9 {
+				t.Errorf("%s: streamed %s %g, expected %g", d.Name, v.name, v.got, v.want)
+			}
+		}
+		if got.N() != 11 || got.DF != 9 {
+			t.Errorf("%s: streamed fit has n %d and %d degrees of freedom", d.Name, got.N(), got.DF)
+		}
+		gotInf, _ := got.Inference(0.95)
+		wantInf, _ := want.Inference(0.95)
+		if math.Abs(gotInf.Slope.StdErr-wantInf.Slope.StdErr) > 1e-9 || math.Abs(float64(gotInf.FP-wantInf.FP)) > 1e-9 {
+			t.Errorf("%s: streamed inference %+v, expected %+v", d.Name, gotInf, wantInf)
+		}
+		r, _ := c.Correlation()
+		cov, _ := c.Covariance(Sample)
+		if math.Abs(r-0.816) > 0.001 || math.Abs(cov-5.5) > 0.01 {
+			t.Errorf("%s: streamed correlation %g and covariance %g", d.Name, r, cov)
+		}
+	}
+
+	var c Comoments
+	c.Add(8, 1)
+	c.Add(8, 2)
+	if _, err := c.Fit(); err != ErrBounds {
+		t.Errorf("Comoments.Fit() with a single x: expected %v, got %v", ErrBounds, err)
+	}
+	if _, err := (Comoments{}).Fit(); err != ErrTooFew {
+		t.Errorf("Comoments.Fit() when empty: expected %v, got %v", ErrTooFew, err)
+	}
+}
+
+func TestStreamCSV(t *testing.T) {
+	f, err := os.Open("testdata/anscombe.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	sets, err := StreamCSV(f, CSVOptions{GroupColumn: "dataset"})
+	if err != nil {
+		t.Fatalf("StreamCSV() returned an error: %v", err)
+	}
+	if len(sets) != 4 || sets[0].Name != "I" || sets[3].Name != "IV" {
+		t.Fatalf("StreamCSV() read %d sets", len(sets))
+	}
+	for i, s := range sets {
+		fit, err := s.Fit()
+		want, _ := LinearRegression(Quartet()[i].X, Quartet()[i].Y)
+		if err != nil || math.Abs(fit.Slope-want.Slope) > 1e-9 || s.N() != 11 {
+			t.Errorf("Set %s: streamed slope %g, %v, expected %g", s.Name, fit.Slope, err, want.Slope)
+		}
+	}
+
+	_, err = StreamCSV(strings.NewReader("x,y\n1,2\n3,NaN\n"), CSVOptions{})
+	var ve *ValueError
+	if !errors.Is(err, ErrNaN) || !errors.As(err, &ve) || ve.Variable != "y" || ve.Index != 1 {
+		t.Errorf("StreamCSV() with a NaN: expected %v at y[1], got %v", ErrNaN, err)
+	}
+	if err != nil && err.Error() != "line 3: y[1] = NaN: Not a number." {
+		t.Errorf("StreamCSV() with a NaN: error %q", err)
+	}
+	_, err = StreamCSV(strings.NewReader("dataset,x,y\nA,1,2\nB,3,4\nB,-Inf,5\n"), CSVOptions{GroupColumn: "dataset"})
+	if !errors.Is(err, ErrInfValue) || !errors.As(err, &ve) || ve.Set != "B" || ve.Variable != "x" || ve.Index != 1 || !math.IsInf(ve.Value, -1) {
+		t.Errorf("StreamCSV() with an infinite value: expected %v at x[1] of set B, got %v", ErrInfValue, err)
+	}
+	if err != nil && !strings.Contains(err.Error(), "line 4") {
+		t.Errorf("StreamCSV() with an infinite value: error %q does not name line 4", err)
+	}
+	if _, err := StreamCSV(strings.NewReader("x,y\n"), CSVOptions{}); err != ErrEmptyInput {
+		t.Errorf("StreamCSV() without rows: expected %v, got %v", ErrEmptyInput, err)
+	}
+}
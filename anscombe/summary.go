@@ -0,0 +1,125 @@
+package anscombe
+
+import (
+	"math"
+
+	"github.com/montanaflynn/stats"
+)
+
+// Summary holds the results of analyzing a single dataset. When y is constant
+// only the descriptive statistics and Fit are set, and Correlation and
+// RSquared are NaN.
+type Summary struct {
+	Name         string           `json:"name"`
+	N            int              `json:"n"`
+	Missing      *Missing         `json:"missing,omitempty"` // nil unless some observations were missing x or y
+	Findings     Findings         `json:"findings"`          // findings of Options.Rules, in rule order
+	DDOF         DDOF             `json:"ddof"`              // variance convention of VarianceX, VarianceY, StdDevX and StdDevY
+	MeanX        float64          `json:"mean_x"`
+	MeanY        float64          `json:"mean_y"`
+	VarianceX    float64          `json:"variance_x"`
+	VarianceY    float64          `json:"variance_y"`
+	StdDevX      float64          `json:"std_dev_x"`
+	StdDevY      float64          `json:"std_dev_y"`
+	Correlation  Float            `json:"correlation"`
+	RSquared     Float            `json:"r_squared"`
+	Fit          Fit              `json:"fit"`
+	Inference    Inference        `json:"inference"`            // inference statistics, zero if Fit.DF < 1
+	Influence    []Influence      `json:"influence"`            // per-observation influence measures, empty if Fit.DF < 2
+	Robust       []RobustFit      `json:"robust"`               // one per estimator in Options.Robust
+	Polynomial   *PolynomialFit   `json:"polynomial,omitempty"` // nil unless Options.Degree is at least 2
+	Associations []Association    `json:"associations"`         // one per measure in Options.Measures
+	Bootstrap    *BootstrapResult `json:"bootstrap,omitempty"`  // nil unless Options.Replicates is positive
+	DescriptionX Description      `json:"description_x"`        // full descriptive statistics of x
+	DescriptionY Description      `json:"description_y"`        // full descriptive statistics of y
+}
+
+// Flagged returns the observations flagged by at least one influence measure.
+func (s Summary) Flagged() []Influence {
+	var flagged []Influence
+	for _, in := range s.Influence {
+		if len(in.Flags) > 0 {
+			flagged = append(flagged, in)
+		}
+	}
+	return flagged
+}
+
+// Summarize handles the missing values of d by opts.Missing, checks it
+// against opts.Rules, fits a least squares line to it and computes its
+// inference and descriptive statistics as configured by opts. It returns
+// Findings.Err if a rule finds a critical problem. N counts the observations
+// analyzed, after any were dropped. A constant y, which the Constant rule
+// warns of, is fitted by a flat line that leaves R-squared, the correlation
+// and every test of the fit undefined, so only the line and the descriptive
+// statistics are computed for it.
+func Summarize(d Dataset, opts Options) (Summary, error) {
+	d, missing := opts.Missing.Apply(d)
+	findings := Check(d, opts.Rules)
+	if err := findings.Err(); err != nil {
+		return Summary{}, err
+	}
+	if err := d.Validate(); err != nil {
+		return Summary{}, err
+	}
+	s := Summary{Name: d.Name, N: d.Len(), DDOF: opts.DDOF, Findings: findings}
+	if missing.Missing > 0 {
+		s.Missing = &missing
+	}
+	var err error
+	s.MeanX, _ = Mean(d.X)
+	s.MeanY, _ = Mean(d.Y)
+	if s.VarianceX, err = Variance(d.X, opts.DDOF); err != nil {
+		return Summary{}, err
+	}
+	s.VarianceY, _ = Variance(d.Y, opts.DDOF)
+	s.StdDevX, _ = StdDev(d.X, opts.DDOF)
+	s.StdDevY, _ = StdDev(d.Y, opts.DDOF)
+	s.DescriptionX, _ = Describe(d.X, opts.DDOF)
+	s.DescriptionY, _ = Describe(d.Y, opts.DDOF)
+	if s.Fit, err = LinearRegression(d.X, d.Y); err != nil {
+		return Summary{}, err
+	}
+	if allEqual(d.Y) {
+		s.Correlation, s.RSquared = Float(math.NaN()), Float(math.NaN())
+		return s, nil
+	}
+	s.RSquared = Float(s.Fit.RSquared())
+	if s.Fit.DF > 0 {
+		if s.Inference, err = s.Fit.Inference(opts.level()); err != nil {
+			return Summary{}, err
+		}
+	}
+	if s.Fit.DF > 1 {
+		if s.Influence, err = s.Fit.Influence(d.X); err != nil {
+			return Summary{}, err
+		}
+	}
+	for _, e := range opts.Robust {
+		s.Robust = append(s.Robust, CompareRobust(e, s.Fit, d.X, d.Y, opts.Seed))
+	}
+	if opts.Degree >= 2 {
+		pf := ComparePolynomial(d.X, d.Y, opts.Degree)
+		s.Polynomial = &pf
+	}
+	s.Associations = Associations(d.X, d.Y, opts.Measures, opts.Permutations, opts.Seed)
+	if opts.Replicates > 0 {
+		b, err := Bootstrap(d.X, d.Y, BootstrapOptions{
+			Method:     opts.Resampling,
+			Replicates: opts.Replicates,
+			Seed:       opts.Seed,
+			Workers:    opts.Workers,
+			Level:      opts.level(),
+		})
+		if err != nil {
+			return Summary{}, err
+		}
+		s.Bootstrap = &b
+	}
+	r, err := stats.Correlation(d.X, d.Y)
+	if err != nil {
+		return Summary{}, err
+	}
+	s.Correlation = Float(r)
+	return s, nil
+}
@@ -0,0 +1,61 @@
+package anscombe
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSummarizeQuartet(t *testing.T) {
+	for _, d := range Quartet() {
+		s, err := Summarize(d, DefaultOptions())
+		if err != nil {
+			t.Fatalf("Summarize(%s) returned an error: %v", d.Name, err)
+		}
+		if s.N != 11 {
+			t.Errorf("%s: expected 11 observations, got %d", d.Name, s.N)
+		}
+		if math.Abs(s.MeanX-9) > 1e-9 {
+			t.Errorf("%s: expected mean of x 9.0, got %f", d.Name, s.MeanX)
+		}
+		if math.Abs(s.VarianceX-11) > 1e-9 {
+			t.Errorf("%s: expected variance of x 11.0, got %f", d.Name, s.VarianceX)
+		}
+		if math.Abs(s.VarianceY-4.12) > 0.01 {
+			t.Errorf("%s: expected variance of y 4.12, got %f", d.Name, s.VarianceY)
+		}
+		if s.DDOF != Sample {
+			t.Errorf("%s: expected the sample variance convention, got %v", d.Name, s.DDOF)
+		}
+		if math.Abs(s.MeanY-7.50) > 0.01 {
+			t.Errorf("%s: expected mean of y 7.50, got %f", d.Name, s.MeanY)
+		}
+		if math.Abs(s.Fit.Slope-0.500) > 0.001 {
+			t.Errorf("%s: expected slope 0.500, got %f", d.Name, s.Fit.Slope)
+		}
+		if math.Abs(s.Fit.Intercept-3.00) > 0.01 {
+			t.Errorf("%s: expected intercept 3.00, got %f", d.Name, s.Fit.Intercept)
+		}
+		if math.Abs(float64(s.Correlation)-0.816) > 0.001 {
+			t.Errorf("%s: expected correlation 0.816, got %f", d.Name, s.Correlation)
+		}
+		if math.Abs(float64(s.RSquared)-0.67) > 0.01 {
+			t.Errorf("%s: expected R-squared 0.67, got %f", d.Name, s.RSquared)
+		}
+	}
+}
+
+func TestSummarizePopulation(t *testing.T) {
+	s, err := Summarize(Quartet()[0], Options{DDOF: Population})
+	if err != nil {
+		t.Fatalf("Summarize() returned an error: %v", err)
+	}
+	if math.Abs(s.VarianceX-10) > 1e-9 || math.Abs(s.StdDevX-math.Sqrt(10)) > 1e-9 {
+		t.Errorf("Expected population variance of x 10.0, got %f", s.VarianceX)
+	}
+	if s.DescriptionX.Variance != s.VarianceX || s.DescriptionY.Mean != s.MeanY || s.DescriptionX.DDOF != Population {
+		t.Errorf("Descriptions disagree with the summary: %+v, %+v", s.DescriptionX, s.DescriptionY)
+	}
+	if s.Inference.Level != 0.95 {
+		t.Errorf("Expected the default confidence level 0.95, got %f", s.Inference.Level)
+	}
+}
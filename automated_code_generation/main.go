@@ -1,206 +1,151 @@
 package main
 
 import (
+	"bytes"
 	"fmt"
 	"log"
 	"os"
+	"strings"
 
-	"github.com/montanaflynn/stats"
-	"gonum.org/v1/plot"
-	"gonum.org/v1/plot/plotter"
+	"github.com/bilguunbilegt/automated_programming/anscombe"
+	"github.com/bilguunbilegt/automated_programming/anscombe/plots"
 	"gonum.org/v1/plot/vg"
-	"gonum.org/v1/plot/vg/draw"
 )
 
-var (
-	ErrEmptyInput = statsError{"Input must not be empty."}
-	ErrNaN        = statsError{"Not a number."}
-	ErrNegative   = statsError{"Must not contain negative values."}
-	ErrZero       = statsError{"Must not contain zero values."}
-	ErrBounds     = statsError{"Input is outside of range."}
-	ErrSize       = statsError{"Must be the same length."}
-	ErrInfValue   = statsError{"Value is infinite."}
-	ErrYCoord     = statsError{"Y Value must be greater than zero."}
-)
-
-type statsError struct {
-	msg string
-}
-
-func (e statsError) Error() string {
-	return e.msg
-}
-
 func main() {
-	// Define the Anscombe dataset
-	anscombe := map[string][][]float64{
-		"x1": {{10, 8, 13, 9, 11, 14, 6, 4, 12, 7, 5}},
-		"y1": {{8.04, 6.95, 7.58, 8.81, 8.33, 9.96, 7.24, 4.26, 10.84, 4.82, 5.68}},
-		"x2": {{10, 8, 13, 9, 11, 14, 6, 4, 12, 7, 5}},
-		"y2": {{9.14, 8.14, 8.74, 8.77, 9.26, 8.1, 6.13, 3.1, 9.13, 7.26, 4.74}},
-		"x3": {{10, 8, 13, 9, 11, 14, 6, 4, 12, 7, 5}},
-		"y3": {{7.46, 6.77, 12.74, 7.11, 7.81, 8.84, 6.08, 5.39, 8.15, 6.42, 5.73}},
-		"x4": {{8, 8, 8, 8, 8, 8, 8, 19, 8, 8, 8}},
-		"y4": {{6.58, 5.76, 7.71, 8.84, 8.47, 7.04, 5.25, 12.5, 5.56, 7.91, 6.89}},
+	if err := run(os.Args[1:]); err != nil {
+		log.Fatal(err)
 	}
+}
 
-	// Creating a file to save the analysis results
-	file, err := os.Create("results.txt")
-	if err != nil {
-		log.Fatalf("Failed to create file: %v", err)
-	}
-	defer file.Close()
-
-	// Perform linear regression analysis and print the summary
-	for i := 1; i <= 4; i++ {
-		x := anscombe[fmt.Sprintf("x%d", i)][0]
-		y := anscombe[fmt.Sprintf("y%d", i)][0]
-
-		if err := checkDataQuality(x, y); err != nil {
-			log.Printf("Data quality issue in set %d: %v\n", i, err)
-			continue
-		}
-
-		series, err := linearRegression(x, y)
+// run analyzes the datasets in the file named by args, or the quartet. Sets
+// that fail are logged, skipped and listed at the end of results.txt; only
+// input and output failures are returned.
+func run(args []string) error {
+	// Load the datasets named on the command line, or use the quartet
+	datasets := anscombe.Quartet()
+	if len(args) > 0 {
+		var err error
+		datasets, err = anscombe.LoadFile(args[0], anscombe.CSVOptions{GroupColumn: "dataset"})
 		if err != nil {
-			log.Printf("Error in linear regression for set %d: %v\n", i, err)
-			continue
+			return fmt.Errorf("failed to load %s: %w", args[0], err)
 		}
+	}
 
-		slope := series[1].Y
-		intercept := series[0].Y
+	// Sample variances and 95% intervals, as in Anscombe's table
+	opts := anscombe.DefaultOptions()
 
-		rSquared, err := calculateRSquared(x, y, intercept, slope)
-		if err != nil {
-			log.Printf("Error in calculating R-squared for set %d: %v\n", i, err)
+	// Analyze every set once, for the report, the plots and results.json
+	results := anscombe.Analyze(datasets, opts)
+
+	// Plot every analyzed set, recording once why a set could not be plotted
+	plotErrs := make([]error, len(results.Sets))
+	for n, r := range results.Sets {
+		i := n + 1
+		d := datasets[n]
+		if r.Error != "" {
+			log.Printf("Error in analysis of set %d: %s\n", i, r.Error)
 			continue
 		}
 
-		correlation, err := stats.Correlation(x, y)
-		if err != nil {
-			log.Printf("Error in calculating correlation for set %d: %v\n", i, err)
+		// Create scatter plot for each dataset
+		if err := createScatterPlot(i, d.X, d.Y, plots.DefaultScatterOptions()); err != nil {
+			log.Printf("Error in scatter plot for set %d: %v\n", i, err)
+			plotErrs[n] = err
 			continue
 		}
-
-		fmt.Printf("Set %d:\n", i)
-		fmt.Printf("Intercept: %.2f, Slope: %.2f, R-squared: %.2f, Correlation: %.2f\n\n", intercept, slope, rSquared, correlation)
-
-		result := fmt.Sprintf("Set %d:\nIntercept: %.2f, Slope: %.2f, R-squared: %.2f, Correlation: %.2f\n\n", i, intercept, slope, rSquared, correlation)
-		fmt.Printf(result)
-
-		// Write the result to the file
-		if _, err := file.WriteString(result); err != nil {
-			log.Fatalf("Failed to write to file: %v", err)
+		// Residual diagnostics: residuals vs fitted, Q-Q, scale-location and leverage
+		if err := saveDiagnostics(fmt.Sprintf("anscombe_set_%d_diagnostics.png", i), d); err != nil {
+			log.Printf("Error in diagnostics for set %d: %v\n", i, err)
+			plotErrs[n] = err
 		}
-		// Create scatter plot for each dataset
-		createScatterPlot(i, x, y)
 	}
-}
+	plotted, unplotted := plotOutcomes(results, datasets, plotErrs)
 
-func checkDataQuality(x, y []float64) error {
-	if len(x) == 0 || len(y) == 0 {
-		return ErrEmptyInput
-	}
-	if len(x) != len(y) {
-		return ErrSize
+	// The report, followed by the sets that could not be plotted and why
+	var result strings.Builder
+	if err := anscombe.WriteReport(&result, results, anscombe.Text, anscombe.DefaultPrecision(anscombe.Text)); err != nil {
+		return err
 	}
-	for _, val := range x {
-		if val != val { // NaN check
-			return ErrNaN
+	if len(unplotted) > 0 {
+		fmt.Fprintf(&result, "\nFailed plots: %d of %d\n", len(unplotted), len(results.Sets))
+		for _, e := range unplotted {
+			fmt.Fprintf(&result, "  %v\n", e)
 		}
 	}
-	for _, val := range y {
-		if val != val { // NaN check
-			return ErrNaN
+	fmt.Print(result.String())
+
+	// Write the result to the file
+	if err := os.WriteFile("results.txt", []byte(result.String()), 0o644); err != nil {
+		return fmt.Errorf("failed to write results.txt: %w", err)
+	}
+
+	// The plotted sets side by side on shared axes
+	if len(plotted) > 0 {
+		if err := saveFacetFigure("anscombe.png", plotted); err != nil {
+			log.Printf("Failed to save anscombe.png: %v\n", err)
 		}
 	}
-	return nil
-}
 
-// func linearRegression(x, y []float64) (intercept, slope, rSquared float64, err error) {
-// 	// Perform linear regression
-// 	series, err := stats.LinearRegression(makeSeries(x, y))
-// 	if err != nil {
-// 		return 0, 0, 0, err
-// 	}
-
-// 	// Extract slope and intercept from the series
-// 	slope = series[1].Y
-// 	intercept = series[0].Y
-
-// 	// Calculate R-squared
-// 	rSquared, err = calculateRSquared(x, y, intercept, slope)
-// 	if err != nil {
-// 		return 0, 0, 0, err
-// 	}
-
-// 	return intercept, slope, rSquared, nil
-// }
-
-func linearRegression(x, y []float64) (stats.Series, error) {
-	// Perform linear regression
-	series, err := stats.LinearRegression(makeSeries(x, y))
-	if err != nil {
-		return nil, err
+	// Write the machine-readable results next to results.txt
+	if err := writeResultsJSON("results.json", results); err != nil {
+		return fmt.Errorf("failed to write results.json: %w", err)
 	}
-	return series, nil
+	return nil
 }
 
-// Helper function to create a series from x and y
-func makeSeries(x, y []float64) stats.Series {
-	if len(x) != len(y) {
-		return nil
-	}
-	series := make(stats.Series, len(x))
-	for i := range x {
-		series[i] = stats.Coordinate{X: x[i], Y: y[i]}
+// plotOutcomes splits the analyzed sets of results into those plotted and
+// those that could not be, given the plotting error of each set. Sets that
+// failed the analysis are in neither.
+func plotOutcomes(results anscombe.Results, datasets []anscombe.Dataset, plotErrs []error) (plotted []anscombe.Dataset, unplotted []*anscombe.SetError) {
+	for n, r := range results.Sets {
+		switch {
+		case r.Error != "":
+		case plotErrs[n] != nil:
+			unplotted = append(unplotted, &anscombe.SetError{Set: r.Name, Err: plotErrs[n]})
+		default:
+			plotted = append(plotted, datasets[n])
+		}
 	}
-	return series
+	return plotted, unplotted
 }
 
-// Function to calculate R-squared
-func calculateRSquared(x, y []float64, intercept, slope float64) (float64, error) {
-	y_mean, err := stats.Mean(y)
+// Function for scatter plot with the fitted line and its 95% bands
+func createScatterPlot(setNumber int, x, y []float64, opts plots.ScatterOptions) error {
+	p, err := plots.Scatter(anscombe.Dataset{Name: fmt.Sprintf("Set %d", setNumber), X: x, Y: y}, opts)
 	if err != nil {
-		return 0, err
+		return err
 	}
 
-	var ss_total, ss_residual float64
-	for i := range x {
-		y_predicted := intercept + slope*x[i]
-		ss_total += (y[i] - y_mean) * (y[i] - y_mean)
-		ss_residual += (y[i] - y_predicted) * (y[i] - y_predicted)
-	}
+	p.Title.Text = fmt.Sprintf("Anscombe's Quartet - Set %d", setNumber)
 
-	return 1 - (ss_residual / ss_total), nil
+	return p.Save(5*vg.Inch, 5*vg.Inch, fmt.Sprintf("anscombe_set_%d.png", setNumber))
 }
 
-// Function for scatter plot
-func createScatterPlot(setNumber int, x, y []float64) {
-	p := plot.New()
-
-	p.Title.Text = fmt.Sprintf("Anscombe's Quartet - Set %d", setNumber)
-	p.X.Label.Text = "X"
-	p.Y.Label.Text = "Y"
-
-	pts := make(plotter.XYs, len(x))
-	for i := range x {
-		pts[i].X = x[i]
-		pts[i].Y = y[i]
+// writeResultsJSON writes results as JSON to path. The document is encoded
+// in memory first, so that an encoding failure leaves no file.
+func writeResultsJSON(path string, results anscombe.Results) error {
+	var buf bytes.Buffer
+	if err := anscombe.WriteResults(&buf, results); err != nil {
+		return err
 	}
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
 
-	scatter, err := plotter.NewScatter(pts)
+// saveFacetFigure draws every dataset as one panel of a grid figure at path.
+func saveFacetFigure(path string, datasets []anscombe.Dataset) error {
+	panels, err := plots.Facet(datasets, plots.DefaultFacetOptions())
 	if err != nil {
-		panic(err)
+		return err
 	}
+	return plots.SaveFacet(panels, 8*vg.Inch, 8*vg.Inch, path)
+}
 
-	scatter.GlyphStyle.Shape = draw.CircleGlyph{}
-	p.Add(scatter)
-	p.Legend.Add(fmt.Sprintf("Set %d", setNumber), scatter)
-
-	if err := p.Save(5*vg.Inch, 5*vg.Inch, fmt.Sprintf("anscombe_set_%d.png", setNumber)); err != nil {
-		panic(err)
+// saveDiagnostics draws the regression diagnostic panels of d to path.
+func saveDiagnostics(path string, d anscombe.Dataset) error {
+	panels, err := plots.Diagnostics(d)
+	if err != nil {
+		return err
 	}
+	return plots.SaveFacet(panels, 8*vg.Inch, 8*vg.Inch, path)
 }
-
-//go:generate goyacc -o main_test.go main_test.y
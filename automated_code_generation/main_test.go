@@ -1,3 +1,53 @@
-// Code generated by goyacc -o main_test.go main_test.y. DO NOT EDIT.
+package main
 
-//line main_test.y:2
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bilguunbilegt/automated_programming/anscombe"
+	"github.com/bilguunbilegt/automated_programming/anscombe/plots"
+)
+
+func TestCreateScatterPlot(t *testing.T) {
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	// Test case 1: the plot is saved under the set number
+	if err := createScatterPlot(1, []float64{1, 2, 3, 4, 5}, []float64{2, 4, 6, 8, 10}, plots.DefaultScatterOptions()); err != nil {
+		t.Fatalf("createScatterPlot() returned an error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "anscombe_set_1.png")); err != nil {
+		t.Errorf("createScatterPlot() did not save the plot: %v", err)
+	}
+
+	// Test case 2: a set with constant x has no line, and the error is returned
+	if err := createScatterPlot(2, []float64{2, 2, 2}, []float64{1, 2, 4}, plots.DefaultScatterOptions()); err == nil {
+		t.Errorf("createScatterPlot() with constant x: expected an error")
+	}
+}
+
+func TestPlotOutcomes(t *testing.T) {
+	datasets := []anscombe.Dataset{{Name: "Good"}, {Name: "Flat"}, {Name: "Diagnostics"}}
+	results := anscombe.Results{Sets: []anscombe.SetResult{
+		{Summary: anscombe.Summary{Name: "Good"}},
+		{Summary: anscombe.Summary{Name: "Flat"}, Error: "x: Values must not all be equal."},
+		{Summary: anscombe.Summary{Name: "Diagnostics"}},
+	}}
+	// Test case 1: a set whose diagnostics failed is only unplotted, and a failed analysis is in neither list
+	plotted, unplotted := plotOutcomes(results, datasets, []error{nil, nil, errors.New("diagnostics failed")})
+	if len(plotted) != 1 || plotted[0].Name != "Good" {
+		t.Errorf("plotOutcomes() plotted %v, expected only Good", plotted)
+	}
+	if len(unplotted) != 1 || unplotted[0].Set != "Diagnostics" {
+		t.Errorf("plotOutcomes() unplotted %v, expected only Diagnostics", unplotted)
+	}
+}
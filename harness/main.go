@@ -0,0 +1,494 @@
+// Command harness runs each implementation of the Anscombe analysis on the
+// same datasets, compares the results.txt and results.json it writes with
+// reference values and prints a correctness scorecard.
+//
+// Run it from this directory:
+//
+//	go run . [-root ..] [-rev revision] [-cases testdata] [-v]
+//
+// Every case in the cases directory is a reference JSON file naming the input
+// file passed to each implementation (none for the built-in quartet) and the
+// values expected for each set, with an absolute tolerance. Values are keyed
+// by their path in the set objects of results.json, and text values by the
+// label they are printed under in results.txt. The implementations are built
+// from the working tree, or with -rev from a git revision of the repository.
+// The exit status is 1 if any implementation fails a check.
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// implementations are the module directories, relative to the repository
+// root, whose commands the harness compares.
+var implementations = []string{"ai_assisted_programming", "ai_generated_code", "automated_code_generation"}
+
+// errBuild fails the checks of an implementation that did not build; the
+// scorecard gives the build error once, above its failed checks.
+var errBuild = errors.New("build failed")
+
+// runTimeout bounds a single run of an implementation on one case.
+const runTimeout = 5 * time.Minute
+
+// Reference describes one case: the input and the values expected from it.
+type Reference struct {
+	Name        string         `json:"-"` // file name without extension
+	Description string         `json:"description"`
+	Input       string         `json:"input"` // dataset file relative to the reference, or empty for the built-in quartet
+	Sets        []SetReference `json:"sets"`
+}
+
+// SetReference is what one set of the results must contain.
+type SetReference struct {
+	Name   string              `json:"name"`
+	Error  bool                `json:"error,omitempty"` // the set cannot be analyzed and must be reported as failed
+	Values map[string]Expected `json:"values,omitempty"`
+	// Text holds the values printed in results.txt, keyed by their label,
+	// with alternative labels separated by "|", e.g. "R-squared|R^2".
+	Text map[string]Expected `json:"text,omitempty"`
+}
+
+// Expected is a reference value and how far a result may be from it.
+type Expected struct {
+	Value     float64 `json:"value"`
+	Tolerance float64 `json:"tolerance"`
+}
+
+// Check is the outcome of one comparison.
+type Check struct {
+	Name   string
+	Passed bool
+	Detail string // why the check failed
+}
+
+// Output is what one run of an implementation left behind.
+type Output struct {
+	Err     error  // nil if the command exited with status 0
+	Stderr  string // the runtime error or else the last line written to standard error
+	Text    []byte // results.txt, nil if missing
+	Results []byte // results.json, nil if missing
+}
+
+// Score is the scorecard entry of one implementation.
+type Score struct {
+	Implementation string
+	BuildErr       error
+	Checks         []Check
+}
+
+// Passed returns the number of checks passed.
+func (s Score) Passed() int {
+	n := 0
+	for _, c := range s.Checks {
+		if c.Passed {
+			n++
+		}
+	}
+	return n
+}
+
+func main() {
+	root := flag.String("root", "..", "repository root holding the implementations")
+	rev := flag.String("rev", "", "git revision to build the implementations from instead of the working tree")
+	casesDir := flag.String("cases", "testdata", "directory of reference cases")
+	verbose := flag.Bool("v", false, "list passed checks as well as failed ones")
+	flag.Parse()
+
+	refs, err := loadReferences(*casesDir)
+	if err != nil {
+		log.Fatalf("Failed to load cases: %v", err)
+	}
+	bin, err := os.MkdirTemp("", "harness")
+	if err != nil {
+		log.Fatalf("Failed to create build directory: %v", err)
+	}
+	defer os.RemoveAll(bin)
+	if *rev != "" {
+		src := filepath.Join(bin, "src")
+		if err := export(*root, *rev, src); err != nil {
+			log.Fatalf("Failed to export %s: %v", *rev, err)
+		}
+		*root = src
+	}
+
+	scores := make([]Score, 0, len(implementations))
+	for _, impl := range implementations {
+		score := Score{Implementation: impl}
+		exe, err := build(filepath.Join(*root, impl), filepath.Join(bin, impl))
+		if err != nil {
+			score.BuildErr = err
+		}
+		for _, ref := range refs {
+			if score.BuildErr != nil {
+				score.Checks = append(score.Checks, compare(ref, Output{Err: errBuild})...)
+				continue
+			}
+			out, err := run(exe, ref)
+			if err != nil {
+				log.Fatalf("Failed to run %s on %s: %v", impl, ref.Name, err)
+			}
+			score.Checks = append(score.Checks, compare(ref, out)...)
+		}
+		scores = append(scores, score)
+	}
+
+	if err := writeScorecard(os.Stdout, refs, scores, *verbose); err != nil {
+		log.Fatalf("Failed to write scorecard: %v", err)
+	}
+	for _, s := range scores {
+		if s.BuildErr != nil || s.Passed() < len(s.Checks) {
+			os.Exit(1)
+		}
+	}
+}
+
+// loadReferences reads every *.json case in dir, in name order.
+func loadReferences(dir string) ([]Reference, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no cases in %s", dir)
+	}
+	sort.Strings(paths)
+	refs := make([]Reference, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var ref Reference
+		if err := json.Unmarshal(data, &ref); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		ref.Name = strings.TrimSuffix(filepath.Base(path), ".json")
+		if ref.Input != "" {
+			if ref.Input, err = filepath.Abs(filepath.Join(dir, ref.Input)); err != nil {
+				return nil, err
+			}
+		}
+		refs = append(refs, ref)
+	}
+	return refs, nil
+}
+
+// export writes the tree of the git revision rev of the repository at root to
+// dir.
+func export(root, rev, dir string) error {
+	cmd := exec.Command("git", "archive", "--format=tar", rev)
+	cmd.Dir = root
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	archive, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("git archive: %v: %s", err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	tr := tar.NewReader(bytes.NewReader(archive))
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(dir, filepath.FromSlash(hdr.Name))
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(path, 0o755)
+		case tar.TypeReg:
+			if err = os.MkdirAll(filepath.Dir(path), 0o755); err == nil {
+				err = writeFile(path, tr, os.FileMode(hdr.Mode).Perm())
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// writeFile copies r to a new file at path.
+func writeFile(path string, r io.Reader, perm os.FileMode) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// build compiles the command in dir to exe.
+func build(dir, exe string) (string, error) {
+	cmd := exec.Command("go", "build", "-o", exe, ".")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("go build: %v: %s", err, bytes.TrimSpace(out))
+	}
+	return exe, nil
+}
+
+// run executes exe on the input of ref in a fresh directory and collects
+// what it wrote there. Only a failure to set up the run is returned as an
+// error; the command's own failure is recorded in the Output.
+func run(exe string, ref Reference) (Output, error) {
+	dir, err := os.MkdirTemp("", "harness-"+ref.Name)
+	if err != nil {
+		return Output{}, err
+	}
+	defer os.RemoveAll(dir)
+
+	ctx, cancel := context.WithTimeout(context.Background(), runTimeout)
+	defer cancel()
+	var args []string
+	if ref.Input != "" {
+		args = append(args, ref.Input)
+	}
+	cmd := exec.CommandContext(ctx, exe, args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stdout = io.Discard
+	cmd.Stderr = &stderr
+
+	out := Output{Err: cmd.Run(), Stderr: lastError(stderr.String())}
+	if out.Text, err = readOptional(filepath.Join(dir, "results.txt")); err != nil {
+		return Output{}, err
+	}
+	if out.Results, err = readOptional(filepath.Join(dir, "results.json")); err != nil {
+		return Output{}, err
+	}
+	return out, nil
+}
+
+// lastError returns the line of stderr that explains a failure: the panic or
+// fatal error of a crash, whose stack trace follows it, or else the last line.
+func lastError(stderr string) string {
+	lines := strings.Split(strings.TrimSpace(stderr), "\n")
+	for _, line := range lines {
+		if strings.HasPrefix(line, "panic: ") || strings.HasPrefix(line, "fatal error: ") {
+			return line
+		}
+	}
+	return lines[len(lines)-1]
+}
+
+// readOptional returns the contents of path, or nil if it does not exist.
+func readOptional(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return data, err
+}
+
+// compare checks out against ref. Every case yields the same checks whatever
+// the output, so implementations are scored out of the same total.
+func compare(ref Reference, out Output) []Check {
+	prefix := ref.Name + ": "
+	exit := Check{Name: prefix + "exits with status 0", Passed: out.Err == nil}
+	if out.Err != nil {
+		exit.Detail = out.Err.Error()
+		if out.Stderr != "" {
+			exit.Detail += ": " + out.Stderr
+		}
+	}
+	text := Check{Name: prefix + "results.txt is not empty", Passed: len(bytes.TrimSpace(out.Text)) > 0}
+	if !text.Passed {
+		text.Detail = "empty or missing"
+	}
+	checks := []Check{exit, text}
+
+	var doc struct {
+		Sets []map[string]any `json:"sets"`
+	}
+	parsed := Check{Name: prefix + "results.json is valid"}
+	switch {
+	case out.Results == nil:
+		parsed.Detail = "missing"
+	default:
+		if err := json.Unmarshal(out.Results, &doc); err != nil {
+			parsed.Detail = err.Error()
+		} else {
+			parsed.Passed = true
+		}
+	}
+	checks = append(checks, parsed)
+
+	sets := make(map[string]map[string]any, len(doc.Sets))
+	for _, s := range doc.Sets {
+		if name, ok := s["name"].(string); ok {
+			sets[name] = s
+		}
+	}
+	analyzed := 0 // sets before want that are not expected to fail
+	for _, want := range ref.Sets {
+		got, found := sets[want.Name]
+		if want.Error {
+			c := Check{Name: prefix + want.Name + " is reported as failed"}
+			msg, _ := got["error"].(string)
+			switch {
+			case !found:
+				c.Detail = "set missing from results.json"
+			case msg == "":
+				c.Detail = "no error recorded"
+			default:
+				c.Passed = true
+			}
+			checks = append(checks, c)
+			continue
+		}
+		paths := make([]string, 0, len(want.Values))
+		for path := range want.Values {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		for _, path := range paths {
+			e := want.Values[path]
+			c := Check{Name: prefix + want.Name + " " + path}
+			v, err := lookup(got, path)
+			switch {
+			case !found:
+				c.Detail = "set missing from results.json"
+			case err != nil:
+				c.Detail = err.Error()
+			case math.Abs(v-e.Value) > e.Tolerance:
+				c.Detail = fmt.Sprintf("got %.15g, expected %.15g ± %g", v, e.Value, e.Tolerance)
+			default:
+				c.Passed = true
+			}
+			checks = append(checks, c)
+		}
+
+		labels := make([]string, 0, len(want.Text))
+		for label := range want.Text {
+			labels = append(labels, label)
+		}
+		sort.Strings(labels)
+		for _, label := range labels {
+			e := want.Text[label]
+			c := Check{Name: prefix + want.Name + " " + label + " in results.txt"}
+			v, err := textValue(out.Text, label, analyzed)
+			switch {
+			case err != nil:
+				c.Detail = err.Error()
+			case math.Abs(v-e.Value) > e.Tolerance:
+				c.Detail = fmt.Sprintf("got %g, expected %.15g ± %g", v, e.Value, e.Tolerance)
+			default:
+				c.Passed = true
+			}
+			checks = append(checks, c)
+		}
+		analyzed++
+	}
+	return checks
+}
+
+// textValue returns the number printed under label for the nth (from 0)
+// analyzed set in text. A value counts if its label starts a line, or
+// follows other "label: number" pairs separated by commas that do, as in
+// "Mean X: 9.00, Mean Y: 7.50"; the labels of lines such as "Huber fit:
+// Intercept: 2.98" are not matched.
+func textValue(text []byte, label string, n int) (float64, error) {
+	alts := strings.Split(label, "|")
+	for i, alt := range alts {
+		alts[i] = regexp.QuoteMeta(alt)
+	}
+	re := regexp.MustCompile(`(?m)^[ \t]*(?:[^:,\n]+: \S+, )*(?:` + strings.Join(alts, "|") + `):[ \t]*([-+]?[0-9.]+(?:[eE][-+]?[0-9]+)?)`)
+	matches := re.FindAllSubmatch(text, -1)
+	if n >= len(matches) {
+		return 0, fmt.Errorf("%s: printed for %d sets, not set %d", label, len(matches), n+1)
+	}
+	return strconv.ParseFloat(string(matches[n][1]), 64)
+}
+
+// lookup returns the number at a dotted path such as "fit.slope" in a
+// decoded JSON object.
+func lookup(obj map[string]any, path string) (float64, error) {
+	var v any = obj
+	for _, key := range strings.Split(path, ".") {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return 0, fmt.Errorf("%s: not an object at %q", path, key)
+		}
+		if v, ok = m[key]; !ok {
+			return 0, fmt.Errorf("%s: no field %q", path, key)
+		}
+	}
+	f, ok := v.(float64)
+	if !ok {
+		return 0, fmt.Errorf("%s: %v is not a number", path, v)
+	}
+	return f, nil
+}
+
+// writeScorecard prints one line per implementation with the share of checks
+// it passed, followed by the checks each one failed, or all checks if
+// verbose.
+func writeScorecard(w io.Writer, refs []Reference, scores []Score, verbose bool) error {
+	names := make([]string, len(refs))
+	for i, ref := range refs {
+		names[i] = ref.Name
+	}
+	fmt.Fprintf(w, "Cases: %s\n\n", strings.Join(names, ", "))
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Implementation\tBuild\tPassed\tScore")
+	for _, s := range scores {
+		build := "ok"
+		if s.BuildErr != nil {
+			build = "failed"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d/%d\t%.1f%%\n", s.Implementation, build, s.Passed(), len(s.Checks), 100*float64(s.Passed())/float64(len(s.Checks)))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	for _, s := range scores {
+		if s.BuildErr != nil {
+			fmt.Fprintf(w, "\n%s: %v\n", s.Implementation, s.BuildErr)
+		}
+		header := false
+		for _, c := range s.Checks {
+			if c.Passed && !verbose {
+				continue
+			}
+			if !header {
+				fmt.Fprintf(w, "\n%s:\n", s.Implementation)
+				header = true
+			}
+			status := "FAIL"
+			if c.Passed {
+				status = "ok"
+			}
+			if c.Detail != "" {
+				fmt.Fprintf(w, "  %-4s %s: %s\n", status, c.Name, c.Detail)
+			} else {
+				fmt.Fprintf(w, "  %-4s %s\n", status, c.Name)
+			}
+		}
+	}
+	_, err := fmt.Fprintln(w)
+	return err
+}
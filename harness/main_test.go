@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+var testReference = Reference{
+	Name: "case",
+	Sets: []SetReference{
+		{Name: "Good", Values: map[string]Expected{
+			"fit.slope": {Value: 0.5, Tolerance: 1e-9},
+			"n":         {Value: 11},
+		}, Text: map[string]Expected{
+			"Slope": {Value: 0.5, Tolerance: 0.005},
+		}},
+		{Name: "Flat", Error: true},
+	},
+}
+
+func TestCompare(t *testing.T) {
+	// Test case 1: a run that matches the reference passes every check
+	out := Output{
+		Text:    []byte("Set Good\n  Slope: 0.500 (std. error 0.118)\n\nSet Flat: Input is outside of range.\n"),
+		Results: []byte(`{"sets": [{"name": "Good", "n": 11, "fit": {"slope": 0.5000000001}}, {"name": "Flat", "error": "Input is outside of range."}]}`),
+	}
+	checks := compare(testReference, out)
+	if len(checks) != 7 {
+		t.Fatalf("compare() returned %d checks, expected 7", len(checks))
+	}
+	for _, c := range checks {
+		if !c.Passed {
+			t.Errorf("Check %q failed: %s", c.Name, c.Detail)
+		}
+	}
+
+	// Test case 2: values out of tolerance and an unreported failure
+	out.Text = []byte("Set Good\n  Slope: 0.52\n")
+	out.Results = []byte(`{"sets": [{"name": "Good", "n": 11, "fit": {"slope": 0.51}}, {"name": "Flat"}]}`)
+	failed := map[string]bool{}
+	for _, c := range compare(testReference, out) {
+		if !c.Passed {
+			failed[c.Name] = true
+		}
+	}
+	if len(failed) != 3 || !failed["case: Good fit.slope"] || !failed["case: Good Slope in results.txt"] || !failed["case: Flat is reported as failed"] {
+		t.Errorf("compare() failed %v, expected both slopes and the Flat error", failed)
+	}
+
+	// Test case 3: a crashed run fails every check but is scored out of the same total
+	crashed := compare(testReference, Output{Err: errors.New("exit status 1"), Stderr: "panic"})
+	if len(crashed) != 7 {
+		t.Fatalf("compare() of a crashed run returned %d checks, expected 7", len(crashed))
+	}
+	for _, c := range crashed {
+		if c.Passed {
+			t.Errorf("Check %q passed on a crashed run", c.Name)
+		}
+	}
+	if crashed[0].Detail != "exit status 1: panic" {
+		t.Errorf("Exit check detail = %q", crashed[0].Detail)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	obj := map[string]any{"fit": map[string]any{"slope": 0.5}, "name": "Set 1"}
+	if v, err := lookup(obj, "fit.slope"); err != nil || v != 0.5 {
+		t.Errorf("lookup(fit.slope) = %g, %v", v, err)
+	}
+	for _, path := range []string{"fit.intercept", "name", "name.x"} {
+		if _, err := lookup(obj, path); err == nil {
+			t.Errorf("lookup(%s): expected an error", path)
+		}
+	}
+}
+
+func TestLastError(t *testing.T) {
+	tests := []struct {
+		stderr, want string
+	}{
+		{"", ""},
+		{"Reading data\n2024/01/01 Failed to write results.txt: disk full\n", "2024/01/01 Failed to write results.txt: disk full"},
+		{"runtime: goroutine stack exceeds limit\nfatal error: stack overflow\n\ngoroutine 1 [running]:\nmain.main()\n", "fatal error: stack overflow"},
+	}
+	for _, tt := range tests {
+		if got := lastError(tt.stderr); got != tt.want {
+			t.Errorf("lastError(%q) = %q, expected %q", tt.stderr, got, tt.want)
+		}
+	}
+}
+
+func TestTextValue(t *testing.T) {
+	text := []byte("Set 1\n  Mean X: 9.00, Mean Y: 7.50\n  Slope: 0.500 (std. error 0.118)\n  Huber fit: Intercept: 2.98, Slope: 0.506\n" +
+		"Set 2\nIntercept: 3.00, Slope: 0.499, R^2: 0.67\n")
+
+	// Test case 1: labels at the start of a line or after other values
+	tests := []struct {
+		label string
+		n     int
+		want  float64
+	}{
+		{"Mean Y", 0, 7.5},
+		{"Slope", 0, 0.5},
+		{"Slope", 1, 0.499},
+		{"R-squared|R^2", 0, 0.67},
+	}
+	for _, tt := range tests {
+		if v, err := textValue(text, tt.label, tt.n); err != nil || v != tt.want {
+			t.Errorf("textValue(%s, %d) = %g, %v, expected %g", tt.label, tt.n, v, err, tt.want)
+		}
+	}
+
+	// Test case 2: the robust fit is not counted, so there is no third slope
+	if _, err := textValue(text, "Slope", 2); err == nil {
+		t.Errorf("textValue(Slope, 2): expected an error")
+	}
+}
+
+func TestWriteScorecard(t *testing.T) {
+	scores := []Score{
+		{Implementation: "good", Checks: []Check{{Name: "a", Passed: true}, {Name: "b", Passed: true}}},
+		{Implementation: "bad", Checks: []Check{{Name: "a", Passed: true}, {Name: "b", Detail: "got 1"}}},
+	}
+	var buf bytes.Buffer
+	if err := writeScorecard(&buf, []Reference{{Name: "case"}}, scores, false); err != nil {
+		t.Fatalf("writeScorecard() returned an error: %v", err)
+	}
+	got := buf.String()
+	for _, want := range []string{"good            ok     2/2     100.0%", "bad             ok     1/2     50.0%", "bad:\n  FAIL b: got 1"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Scorecard does not contain %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "good:\n") {
+		t.Errorf("Scorecard lists checks of an implementation that passed all of them:\n%s", got)
+	}
+}